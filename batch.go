@@ -0,0 +1,186 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// batchParamLimits holds the most bindvars a statement may hold for drivers
+// whose limit is not the one of their bindtype.  SQLite before 3.32 allows
+// 999 variables, and later versions 32766 unless built otherwise.
+var batchParamLimits = map[string]int{
+	"sqlite3":   999,
+	"nrsqlite3": 999,
+	"sqlite":    999,
+}
+
+// bindTypeParamLimits holds the most bindvars a statement may hold for each
+// bindtype, for drivers not in batchParamLimits.
+var bindTypeParamLimits = map[int]int{
+	binder.QUESTION: 65535, // mysql
+	binder.DOLLAR:   65535, // postgres
+	binder.NAMED:    65535, // oracle
+	binder.AT:       2100,  // sqlserver
+}
+
+// maxBatchParams returns the most bindvars a statement may hold for the
+// driver, erring on the low side for unknown drivers.
+func maxBatchParams(driverName string) int {
+	if n, ok := batchParamLimits[driverName]; ok {
+		return n
+	}
+	if n, ok := bindTypeParamLimits[binder.Default.Type(driverName)]; ok {
+		return n
+	}
+	return 999
+}
+
+// BatchOptions configures NamedExecBatch.
+type BatchOptions struct {
+	// MaxParams is the most bindvars a single statement may hold.  If 0, the
+	// limit of the driver is used.
+	MaxParams int
+	// ChunkSize, if not 0, caps the number of elements bound per statement.
+	// It can keep statements under limits which are not counted in
+	// parameters, such as MySQL's max_allowed_packet.
+	ChunkSize int
+	// ContinueOnError executes the remaining chunks after one fails.  It has
+	// no effect when NamedExecBatch runs the batch in its own transaction,
+	// which the first error rolls back.
+	ContinueOnError bool
+}
+
+// A BatchChunkError is the error of executing one chunk of a batch, holding
+// the elements from Start up to but not including End.
+type BatchChunkError struct {
+	Start, End int
+	Err        error
+}
+
+func (e BatchChunkError) Error() string {
+	return fmt.Sprintf("batch elements %d to %d: %v", e.Start, e.End-1, e.Err)
+}
+
+func (e BatchChunkError) Unwrap() error {
+	return e.Err
+}
+
+// A BatchError holds the errors of the chunks of a batch which failed.
+type BatchError []BatchChunkError
+
+func (e BatchError) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d batch chunks failed, first: %v", len(e), e[0])
+}
+
+func (e BatchError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, c := range e {
+		errs[i] = c
+	}
+	return errs
+}
+
+// NamedExecBatch executes a named query with a VALUES list, such as a bulk
+// INSERT, for each element of a slice or array of structs or maps, like
+// NamedExecContext does with a slice.  Rather than binding every element in a
+// single statement, it splits the slice into chunks which keep each statement
+// within the parameter limit of the driver, or opts.MaxParams.
+//
+// If e is a *DB, the chunks are executed in a transaction which is rolled
+// back if any of them fails.  NamedExecBatch returns the total number of rows
+// affected, and a BatchError if any chunk failed.
+func NamedExecBatch(ctx context.Context, e ExtContext, query string, slice any, opts BatchOptions) (int64, error) {
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+	case reflect.Array:
+		// make the array addressable so that it can be sliced
+		a := reflect.New(v.Type()).Elem()
+		a.Set(v)
+		v = a
+	default:
+		return 0, fmt.Errorf("sqlx.NamedExecBatch: expected a slice or an array, got %T", slice)
+	}
+	if v.Len() == 0 {
+		return 0, nil
+	}
+
+	cache := namedCacheFor(e)
+	q, err := cache.parse(query, binder.QUESTION)
+	if err != nil {
+		return 0, err
+	}
+	size := v.Len()
+	if maxParams := opts.MaxParams; len(q.names) > 0 {
+		if maxParams <= 0 {
+			maxParams = maxBatchParams(e.DriverName())
+		}
+		size = max(maxParams/len(q.names), 1)
+	}
+	if opts.ChunkSize > 0 {
+		size = min(size, opts.ChunkSize)
+	}
+
+	if db, ok := e.(*DB); ok {
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		opts.ContinueOnError = false
+		n, err := execBatch(ctx, tx, query, v, size, opts)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		return n, tx.Commit()
+	}
+	return execBatch(ctx, e, query, v, size, opts)
+}
+
+// execBatch executes query for the elements of v, size at a time.
+func execBatch(ctx context.Context, e ExtContext, query string, v reflect.Value, size int, opts BatchOptions) (int64, error) {
+	bindType := binder.Default.Type(e.DriverName())
+	m, cache := mapperFor(e), namedCacheFor(e)
+
+	var total int64
+	var errs BatchError
+	for start := 0; start < v.Len(); start += size {
+		end := min(start+size, v.Len())
+		n, err := execChunk(ctx, e, bindType, query, v.Slice(start, end).Interface(), m, cache)
+		if err != nil {
+			errs = append(errs, BatchChunkError{Start: start, End: end, Err: err})
+			if !opts.ContinueOnError {
+				break
+			}
+			continue
+		}
+		total += n
+	}
+	if errs != nil {
+		return total, errs
+	}
+	return total, nil
+}
+
+// execChunk binds chunk to query as NamedExec does and executes it.
+func execChunk(ctx context.Context, e ExtContext, bindType int, query string, chunk any, m *reflectx.Mapper, cache *NamedCache) (int64, error) {
+	q, args, err := bindArray(bindType, query, optionalFor(e, chunk), m, cache)
+	if err != nil {
+		return 0, err
+	}
+	res, err := e.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
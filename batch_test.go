@@ -0,0 +1,81 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// batchCode is a telcode which fails to bind when negative.
+type batchCode int
+
+func (c batchCode) Value() (driver.Value, error) {
+	if c < 0 {
+		return nil, errors.New("negative telcode")
+	}
+	return int64(c), nil
+}
+
+func TestNamedExecBatch(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		type place struct {
+			Country string    `db:"country"`
+			TelCode batchCode `db:"telcode"`
+		}
+		count := func(q Queryer) int {
+			var n int
+			if err := Get(q, &n, "SELECT count(*) FROM place"); err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+		query := "INSERT INTO place (country, telcode) VALUES (:country, :telcode)"
+
+		places := make([]place, 1000)
+		for i := range places {
+			places[i] = place{Country: fmt.Sprintf("c%d", i), TelCode: batchCode(i)}
+		}
+		n, err := NamedExecBatch(ctx, db, query, places, BatchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1000 || count(db) != 1000 {
+			t.Errorf("expected 1000 rows, got %d affected and %d inserted", n, count(db))
+		}
+
+		// a failing chunk rolls back the whole batch
+		db.MustExecContext(ctx, "DELETE FROM place")
+		places = places[:5]
+		places[3].TelCode = -1
+		n, err = NamedExecBatch(ctx, db, query, places, BatchOptions{MaxParams: 5})
+		var batchErr BatchError
+		if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[0].Start != 2 || batchErr[0].End != 4 {
+			t.Fatalf("expected the second chunk to fail, got %v", err)
+		}
+		if n != 0 || count(db) != 0 {
+			t.Errorf("expected the batch to be rolled back, got %d affected and %d inserted", n, count(db))
+		}
+
+		// within a caller's transaction, later chunks may still run
+		tx := db.MustBeginTx(ctx, nil)
+		defer tx.Rollback()
+		maps := []map[string]any{
+			{"country": "a", "telcode": 1},
+			{"country": "b", "telcode": batchCode(-1)},
+			{"country": "c", "telcode": 3},
+		}
+		n, err = NamedExecBatch(ctx, tx, query, maps, BatchOptions{ChunkSize: 1, ContinueOnError: true})
+		if !errors.As(err, &batchErr) || len(batchErr) != 1 || batchErr[0].Start != 1 {
+			t.Fatalf("expected the second chunk to fail, got %v", err)
+		}
+		if n != 2 || count(tx) != 2 {
+			t.Errorf("expected 2 rows, got %d affected and %d inserted", n, count(tx))
+		}
+
+		if _, err = NamedExecBatch(ctx, tx, query, place{}, BatchOptions{}); err == nil {
+			t.Error("expected an error for a non-slice argument")
+		}
+	})
+}
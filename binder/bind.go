@@ -45,6 +45,13 @@ const (
 	DOLLAR
 	NAMED
 	AT
+	// ATNAME and COLONNAME bind named queries using the driver's own named
+	// parameters, as @name for sqlserver or :name for godror, passing the args
+	// as sql.NamedArg.  Queries bound to a slice of args, and queries rebound
+	// from '?', still use positional parameters.  No driver uses them unless
+	// registered with Driver.
+	ATNAME
+	COLONNAME
 )
 
 var defaultBinds = map[int][]string{
@@ -96,9 +103,9 @@ func (Binder) Rebind(bindType int, query string) string {
 		switch bindType {
 		case DOLLAR:
 			rqb = append(rqb, '$')
-		case NAMED:
+		case NAMED, COLONNAME:
 			rqb = append(rqb, ':', 'a', 'r', 'g')
-		case AT:
+		case AT, ATNAME:
 			rqb = append(rqb, '@', 'p')
 		}
 
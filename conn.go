@@ -13,7 +13,63 @@ type Conn struct {
 	*sql.Conn
 	driverName string
 	unsafe     bool
+	strict     bool
+	nullZero   bool
 	Mapper     *reflectx.Mapper
+	namedCache *NamedCache
+	// optionalNamed makes all named parameters optional, see OptionalNamed.
+	optionalNamed bool
+	// namedDefaults are bound to missing optional parameters, see
+	// NamedDefaults.
+	namedDefaults map[string]any
+}
+
+// DriverName returns the driverName of the DB this connection was taken from.
+func (c *Conn) DriverName() string {
+	return c.driverName
+}
+
+// Unsafe returns a version of Conn which will silently succeed to scan when
+// columns in the SQL result have no fields in the destination struct.
+// sqlx.Stmt and sqlx.Tx which are created from this Conn will inherit its
+// safety behavior.
+func (c *Conn) Unsafe() *Conn {
+	cp := *c
+	cp.unsafe = true
+	return &cp
+}
+
+// Strict returns a version of Conn which will fail to scan when fields of the
+// destination struct are not filled by any column in the SQL result.
+func (c *Conn) Strict() *Conn {
+	cp := *c
+	cp.strict = true
+	return &cp
+}
+
+// NullTolerant returns a version of Conn which scans NULL into the zero value
+// of struct fields which cannot hold NULL instead of failing.
+func (c *Conn) NullTolerant() *Conn {
+	cp := *c
+	cp.nullZero = true
+	return &cp
+}
+
+// OptionalNamed returns a version of Conn on which every parameter of named
+// queries is optional, binding its default or NULL when missing.
+func (c *Conn) OptionalNamed() *Conn {
+	cp := *c
+	cp.optionalNamed = true
+	return &cp
+}
+
+// NamedDefaults returns a version of Conn which binds optional named
+// parameters missing from the arg to their value in defaults rather than
+// NULL, as DB.NamedDefaults does.
+func (c *Conn) NamedDefaults(defaults map[string]any) *Conn {
+	cp := *c
+	cp.namedDefaults = mergeDefaults(c.namedDefaults, defaults)
+	return &cp
 }
 
 // BeginTxx begins a transaction and returns an *sqlx.Tx instead of an
@@ -28,7 +84,7 @@ func (c *Conn) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: c.driverName, unsafe: c.unsafe, Mapper: c.Mapper}, err
+	return &Tx{Tx: tx, driverName: c.driverName, unsafe: c.unsafe, strict: c.strict, nullZero: c.nullZero, Mapper: c.Mapper, namedCache: c.namedCache, optionalNamed: c.optionalNamed, namedDefaults: c.namedDefaults}, err
 }
 
 // SelectContext using this Conn.
@@ -59,17 +115,55 @@ func (c *Conn) QueryxContext(ctx context.Context, query string, args ...any) (*R
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: c.unsafe, Mapper: c.Mapper}, err
+	return &Rows{Rows: r, unsafe: c.unsafe, strict: c.strict, nullZero: c.nullZero, Mapper: c.Mapper}, err
 }
 
 // QueryRowxContext queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (c *Conn) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
 	rows, err := c.Conn.QueryContext(ctx, query, args...)
-	return &Row{rows: rows, err: err, unsafe: c.unsafe, Mapper: c.Mapper}
+	return &Row{rows: rows, err: err, unsafe: c.unsafe, strict: c.strict, nullZero: c.nullZero, Mapper: c.Mapper}
 }
 
 // Rebind a query within a Conn's bindvar type.
 func (c *Conn) Rebind(query string) string {
 	return binder.Default.Rebind(binder.Default.Type(c.driverName), query)
 }
+
+// BindNamed binds a query using the Conn's bindvar type.
+func (c *Conn) BindNamed(query string, arg any) (string, []any, error) {
+	return bindNamedMapper(binder.Default.Type(c.driverName), query, optionalFor(c, arg), c.Mapper, namedCacheFor(c))
+}
+
+// NamedQueryContext using this Conn.
+// Any named placeholder parameters are replaced with fields from arg.
+func (c *Conn) NamedQueryContext(ctx context.Context, query string, arg any) (*Rows, error) {
+	return NamedQueryContext(ctx, c, query, arg)
+}
+
+// NamedExecContext using this Conn.
+// Any named placeholder parameters are replaced with fields from arg.
+func (c *Conn) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	return NamedExecContext(ctx, c, query, arg)
+}
+
+// PrepareNamedContext returns an sqlx.NamedStmt prepared on this Conn.
+func (c *Conn) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error) {
+	return prepareNamedContext(ctx, c, query)
+}
+
+// PrepareNamedValidatedContext returns an sqlx.NamedStmt prepared on this
+// Conn, once the names of the query are checked against the type of sample as
+// by ValidateNamed.  The sample may also be a reflect.Type.
+func (c *Conn) PrepareNamedValidatedContext(ctx context.Context, query string, sample any) (*NamedStmt, error) {
+	if err := validateSample(c, query, sample); err != nil {
+		return nil, err
+	}
+	return prepareNamedContext(ctx, c, query)
+}
+
+// MustExecContext (panic) runs MustExec using this Conn.
+// Any placeholder parameters are replaced with supplied args.
+func (c *Conn) MustExecContext(ctx context.Context, query string, args ...any) sql.Result {
+	return MustExecContext(ctx, c, query, args...)
+}
@@ -0,0 +1,86 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+)
+
+var _ ExtContext = &Conn{}
+var _ namedPreparerContext = &Conn{}
+
+func TestConnNamed(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		conn, err := db.Connx(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if conn.DriverName() != db.DriverName() {
+			t.Errorf("expected driver %s, got %s", db.DriverName(), conn.DriverName())
+		}
+		if mapperFor(conn) != db.Mapper {
+			t.Error("expected the Conn to use the mapper of its DB")
+		}
+
+		// temp tables only live on the connection which created them
+		conn.MustExecContext(ctx, "CREATE TEMPORARY TABLE scratch (name text, code integer)")
+		_, err = conn.NamedExecContext(ctx, "INSERT INTO scratch (name, code) VALUES (:name, :code)",
+			[]map[string]any{{"name": "a", "code": 1}, {"name": "b", "code": 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := conn.NamedQueryContext(ctx, "SELECT name FROM scratch WHERE code IN (:codes)", map[string]any{"codes": []int{1, 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		if err = scanAll(rows, &names, false); err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if len(names) != 2 {
+			t.Errorf("expected 2 names, got %v", names)
+		}
+
+		type scratch struct {
+			Name string `db:"name"`
+		}
+		ns, err := conn.PrepareNamedValidatedContext(ctx, "SELECT * FROM scratch WHERE name = :name", scratch{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ns.Close()
+		var s scratch
+		if err = ns.GetContext(ctx, &s, scratch{Name: "b"}); err == nil {
+			t.Error("expected an error for the missing code field")
+		}
+		if err = ns.Unsafe().GetContext(ctx, &s, scratch{Name: "b"}); err != nil || s.Name != "b" {
+			t.Errorf("expected to get b, got %v %#v", err, s)
+		}
+
+		unsafe := conn.Unsafe()
+		if !isUnsafe(unsafe) || isUnsafe(conn) {
+			t.Error("expected only the unsafe Conn to be unsafe")
+		}
+		ns, err = unsafe.PrepareNamedContext(ctx, "SELECT * FROM scratch WHERE name = :name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ns.Close()
+		if err = ns.GetContext(ctx, &s, scratch{Name: "a"}); err != nil || s.Name != "a" {
+			t.Errorf("expected to get a, got %v %#v", err, s)
+		}
+
+		q, args, err := conn.BindNamed("SELECT :name", scratch{Name: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q != conn.Rebind("SELECT ?") || len(args) != 1 {
+			t.Errorf("unexpected binding: %s %v", q, args)
+		}
+	})
+}
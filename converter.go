@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// A converter is a reflectx.Converter found for a field type, possibly
+// through a pointer.
+type converter struct {
+	reflectx.Converter
+	deref bool
+}
+
+// converterFor returns the converter registered on m for t, or for the type t
+// points to.
+func converterFor(m *reflectx.Mapper, t reflect.Type) (converter, bool) {
+	if c, ok := m.Converter(t); ok {
+		return converter{Converter: c}, true
+	}
+	if t.Kind() == reflect.Pointer {
+		if c, ok := m.Converter(t.Elem()); ok {
+			return converter{Converter: c, deref: true}, true
+		}
+	}
+	return converter{}, false
+}
+
+// scan stores src in the field f, allocating or clearing f if the converter
+// was found through a pointer.
+func (c converter) scan(src any, f reflect.Value) error {
+	if c.deref {
+		if src == nil {
+			f.SetZero()
+			return nil
+		}
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		f = f.Elem()
+	}
+	return c.Scan(src, f)
+}
+
+// value returns the driver value for the field f.
+func (c converter) value(f reflect.Value) (driver.Value, error) {
+	if c.deref {
+		if f.IsNil() {
+			return nil, nil
+		}
+		f = f.Elem()
+	}
+	return c.Value(f)
+}
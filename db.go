@@ -13,7 +13,15 @@ type DB struct {
 	*sql.DB
 	driverName string
 	unsafe     bool
+	strict     bool
+	nullZero   bool
 	Mapper     *reflectx.Mapper
+	namedCache *NamedCache
+	// optionalNamed makes all named parameters optional, see OptionalNamed.
+	optionalNamed bool
+	// namedDefaults are bound to missing optional parameters, see
+	// NamedDefaults.
+	namedDefaults map[string]any
 }
 
 // NewDb returns a new sqlx DB wrapper for a pre-existing *sql.DB.  The
@@ -21,7 +29,7 @@ type DB struct {
 //
 //lint:ignore ST1003 changing this would break the package interface.
 func NewDb(db *sql.DB, driverName string) *DB {
-	return &DB{DB: db, driverName: driverName, Mapper: mapper()}
+	return &DB{DB: db, driverName: driverName, Mapper: mapper(), namedCache: NewNamedCache(DefaultNamedCacheSize)}
 }
 
 // DriverName returns the driverName passed to the Open function for this DB.
@@ -45,12 +53,74 @@ func (db *DB) Rebind(query string) string {
 // sqlx.Stmt and sqlx.Tx which are created from this DB will inherit its
 // safety behavior.
 func (db *DB) Unsafe() *DB {
-	return &DB{DB: db.DB, driverName: db.driverName, unsafe: true, Mapper: db.Mapper}
+	c := *db
+	c.unsafe = true
+	return &c
+}
+
+// Strict returns a version of DB which will fail to scan when fields of the
+// destination struct are not filled by any column in the SQL result.  Fields
+// tagged with the `optional` option are exempt.  sqlx.Stmt and sqlx.Tx which
+// are created from this DB will inherit its strictness.
+func (db *DB) Strict() *DB {
+	c := *db
+	c.strict = true
+	return &c
+}
+
+// NullTolerant returns a version of DB which scans NULL into the zero value of
+// struct fields which cannot hold NULL, such as string or int, instead of
+// failing.  Fields with a `default` option, as in `db:"score,default=0"`, get
+// that value instead, with or without this mode.  sqlx.Stmt, sqlx.Tx and
+// sqlx.Rows which are created from this DB will inherit this behavior.
+func (db *DB) NullTolerant() *DB {
+	c := *db
+	c.nullZero = true
+	return &c
+}
+
+// OptionalNamed returns a version of DB on which every parameter of named
+// queries is optional, as if written :name?, so that names missing from the
+// arg bind their default or NULL instead of failing.  sqlx.Tx and
+// sqlx.NamedStmt which are created from this DB will inherit this behavior.
+func (db *DB) OptionalNamed() *DB {
+	c := *db
+	c.optionalNamed = true
+	return &c
+}
+
+// NamedDefaults returns a version of DB which binds optional named parameters
+// missing from the arg to their value in defaults rather than NULL.  Defaults
+// of the DB it is called on are kept unless overridden, and those given with
+// OptionalArgs.WithDefaults take precedence.  sqlx.Tx and sqlx.NamedStmt which
+// are created from this DB will inherit the defaults.
+func (db *DB) NamedDefaults(defaults map[string]any) *DB {
+	c := *db
+	c.namedDefaults = mergeDefaults(db.namedDefaults, defaults)
+	return &c
+}
+
+// SetNamedCacheSize sets the number of compiled named queries kept by the DB,
+// which it shares with the DBs and Txs derived from it.  A size of 0 or less
+// disables the cache.
+func (db *DB) SetNamedCacheSize(size int) {
+	if db.namedCache == nil {
+		db.namedCache = NewNamedCache(size)
+		return
+	}
+	db.namedCache.Resize(size)
+}
+
+// NamedCacheStats returns the statistics of the DB's cache of compiled named
+// queries.  A DB without a cache of its own, which uses the package's default
+// cache, reports zero stats.
+func (db *DB) NamedCacheStats() NamedCacheStats {
+	return db.namedCache.Stats()
 }
 
 // BindNamed binds a query using the DB driver's bindvar type.
 func (db *DB) BindNamed(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(binder.Default.Type(db.driverName), query, arg, db.Mapper)
+	return bindNamedMapper(binder.Default.Type(db.driverName), query, optionalFor(db, arg), db.Mapper, namedCacheFor(db))
 }
 
 // NamedQuery using this DB.
@@ -94,7 +164,7 @@ func (db *DB) Beginx() (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper, namedCache: db.namedCache, optionalNamed: db.optionalNamed, namedDefaults: db.namedDefaults}, err
 }
 
 // Queryx queries the database and returns an *sqlx.Rows.
@@ -104,14 +174,14 @@ func (db *DB) Queryx(query string, args ...any) (*Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Rows{Rows: r, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper}, err
 }
 
 // QueryRowx queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) QueryRowx(query string, args ...any) *Row {
 	rows, err := db.DB.Query(query, args...)
-	return &Row{rows: rows, err: err, unsafe: db.unsafe, Mapper: db.Mapper}
+	return &Row{rows: rows, err: err, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper}
 }
 
 // MustExec (panic) runs MustExec using this database.
@@ -129,3 +199,13 @@ func (db *DB) Preparex(query string) (*Stmt, error) {
 func (db *DB) PrepareNamed(query string) (*NamedStmt, error) {
 	return prepareNamed(db, query)
 }
+
+// PrepareNamedValidated returns an sqlx.NamedStmt once the names of the query
+// are checked against the type of sample as by ValidateNamed.  The sample may
+// also be a reflect.Type.
+func (db *DB) PrepareNamedValidated(query string, sample any) (*NamedStmt, error) {
+	if err := validateSample(db, query, sample); err != nil {
+		return nil, err
+	}
+	return prepareNamed(db, query)
+}
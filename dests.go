@@ -0,0 +1,127 @@
+package sqlx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// Destinations is a list of scan destinations for a single row, created with
+// Dests.
+type Destinations []any
+
+// Dests groups several destinations so that a single row can be scanned into
+// them with Get or GetContext:
+//
+//	var count int
+//	var latest time.Time
+//	err := sqlx.GetContext(ctx, db, sqlx.Dests(&count, &latest), "SELECT count(*), max(created_at) FROM events")
+//
+// Columns are assigned positionally.  A scannable destination takes a single
+// column, while a struct destination takes the consecutive columns which map
+// to its fields, leaving at least one column for each destination after it.
+// Every column must be consumed unless the source is unsafe.
+func Dests(dest ...any) Destinations {
+	return Destinations(dest)
+}
+
+// A destPart is a destination along with the columns it takes.
+type destPart struct {
+	v          reflect.Value
+	start, end int
+	scanner    *structScanner
+}
+
+func (r *Row) scanDests(dests Destinations) error {
+	defer r.rows.Close()
+
+	columns, err := r.Columns()
+	if err != nil {
+		return err
+	}
+
+	parts := make([]destPart, len(dests))
+	c := 0
+	for i, dest := range dests {
+		v := reflect.ValueOf(dest)
+		if v.Kind() != reflect.Pointer {
+			return fmt.Errorf("must pass a pointer, not a value, to destination %d", i)
+		}
+		if v.IsNil() {
+			return fmt.Errorf("nil pointer passed to destination %d", i)
+		}
+		// allocate pointers to structs, so that they can be filled
+		for v.Elem().Kind() == reflect.Pointer && !isScannable(reflectx.Deref(v.Elem().Type())) {
+			if v.Elem().IsNil() {
+				v.Elem().Set(reflect.New(v.Elem().Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		if c >= len(columns) {
+			return fmt.Errorf("no column left for destination %d (%s) in result", i, v.Type())
+		}
+		parts[i] = destPart{v: v, start: c, end: c + 1}
+		base := reflectx.Deref(v.Type())
+		if isScannable(base) {
+			c++
+			continue
+		}
+
+		// take the mapped columns, keeping one for each remaining destination
+		tm := r.Mapper.TypeMap(base)
+		limit := len(columns) - (len(dests) - i - 1)
+		end := c
+		for end < limit {
+			if _, ok := tm.Names[columns[end]]; !ok {
+				break
+			}
+			end++
+		}
+		if end == c {
+			return fmt.Errorf("missing destination name %s in %T", columns[c], dest)
+		}
+		s, err := newStructScanner(r.Mapper, base, columns[c:end], r.nullZero)
+		if err != nil {
+			return err
+		}
+		if r.strict {
+			if err := s.checkFilled(v.Interface()); err != nil {
+				return err
+			}
+		}
+		parts[i].end, parts[i].scanner = end, s
+		c = end
+	}
+	if c < len(columns) && !r.unsafe {
+		return fmt.Errorf("missing destination for column %s", columns[c])
+	}
+
+	values := make([]any, len(columns))
+	for _, p := range parts {
+		if p.scanner == nil {
+			values[p.start] = p.v.Interface()
+			continue
+		}
+		if err := p.scanner.prepare(p.v, values[p.start:p.end]); err != nil {
+			return err
+		}
+	}
+	for i := c; i < len(columns); i++ {
+		values[i] = new(any)
+	}
+
+	if err := r.Scan(values...); err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if p.scanner == nil {
+			continue
+		}
+		if err := p.scanner.finish(p.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package sqlx
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// A polyScanner scans rows into an interface type registered with
+// Mapper.RegisterDiscriminator.  Each row is scanned once into raw holders;
+// the value of the discriminator column selects the concrete type, and the
+// raw values are then assigned to a new value of that type.  A structScanner
+// is kept for each concrete type, so that the mapping of columns to fields is
+// only worked out once.
+type polyScanner struct {
+	m        *reflectx.Mapper
+	d        reflectx.Discriminator
+	columns  []string
+	column   int
+	unsafe   bool
+	strict   bool
+	nullZero bool
+	scanners map[reflect.Type]*structScanner
+	values   []any
+	raw      []any
+	rawPtrs  []any
+}
+
+// newPolyScanner returns a polyScanner for rows, or nil if no discriminator is
+// registered for t.
+func newPolyScanner(rows rowsi, m *reflectx.Mapper, t reflect.Type) (*polyScanner, error) {
+	d, ok := m.Discriminator(t)
+	if !ok {
+		return nil, nil
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	column := slices.Index(columns, d.Column)
+	if column < 0 {
+		return nil, fmt.Errorf("missing discriminator column %s for %s", d.Column, t)
+	}
+	p := &polyScanner{
+		m:        m,
+		d:        d,
+		columns:  columns,
+		column:   column,
+		unsafe:   isUnsafe(rows),
+		strict:   isStrict(rows),
+		nullZero: isNullTolerant(rows),
+		scanners: map[reflect.Type]*structScanner{},
+		values:   make([]any, len(columns)),
+		raw:      make([]any, len(columns)),
+		rawPtrs:  make([]any, len(columns)),
+	}
+	for i := range p.raw {
+		p.rawPtrs[i] = &p.raw[i]
+	}
+	return p, nil
+}
+
+// scan scans the current row of rows into a new value of the type selected by
+// the discriminator column.
+func (p *polyScanner) scan(rows rowsi) (reflect.Value, error) {
+	if err := rows.Scan(p.rawPtrs...); err != nil {
+		return reflect.Value{}, err
+	}
+	var key string
+	switch kind := p.raw[p.column].(type) {
+	case nil:
+		return reflect.Value{}, fmt.Errorf("NULL discriminator column %s", p.d.Column)
+	case []byte:
+		key = string(kind)
+	default:
+		key = fmt.Sprint(kind)
+	}
+	t, ok := p.d.Types[key]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("no type registered for %s %q", p.d.Column, key)
+	}
+
+	base := reflectx.Deref(t)
+	s, err := p.scanner(base)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	vp := reflect.New(base)
+	if err := s.prepare(vp, p.values); err != nil {
+		return reflect.Value{}, err
+	}
+	for i, dest := range p.values {
+		if err := convertAssign(dest, p.raw[i]); err != nil {
+			return reflect.Value{}, fmt.Errorf("sql: Scan error on column index %d, name %q: %w", i, p.columns[i], err)
+		}
+	}
+	if err := s.finish(vp); err != nil {
+		return reflect.Value{}, err
+	}
+	if t.Kind() == reflect.Pointer {
+		return vp, nil
+	}
+	return vp.Elem(), nil
+}
+
+// scanner returns the structScanner for base, building it on first use.  The
+// discriminator column need not map to a field.
+func (p *polyScanner) scanner(base reflect.Type) (*structScanner, error) {
+	if s, ok := p.scanners[base]; ok {
+		return s, nil
+	}
+	s, err := newStructScanner(p.m, base, p.columns, p.nullZero)
+	if err != nil {
+		return nil, err
+	}
+	if !p.unsafe {
+		for i, traversal := range s.fields {
+			if len(traversal) == 0 && i != p.column {
+				return nil, fmt.Errorf("missing destination name %s in %s", p.columns[i], base)
+			}
+		}
+	}
+	if p.strict {
+		if err := s.checkFilled(reflect.New(base).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	p.scanners[base] = s
+	return s, nil
+}
+
+// convertAssign stores src, a column value scanned into an any, in the scan
+// destination dest, converting it the way database/sql's Rows.Scan does.
+func convertAssign(dest, src any) error {
+	switch d := dest.(type) {
+	case *any:
+		*d = src
+		return nil
+	case sql.Scanner:
+		return d.Scan(src)
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("destination not a pointer")
+	}
+	return assignValue(dv.Elem(), src)
+}
+
+// assignValue stores src in dv, converting it as convertAssign does.
+func assignValue(dv reflect.Value, src any) error {
+	if src == nil {
+		switch dv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.SetZero()
+			return nil
+		}
+		return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
+	}
+	if dv.Kind() == reflect.Pointer {
+		p := reflect.New(dv.Type().Elem())
+		if err := convertAssign(p.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	if b, ok := src.([]byte); ok && dv.Kind() == reflect.Slice && dv.Type().Elem().Kind() == reflect.Uint8 {
+		dv.SetBytes(bytes.Clone(b))
+		return nil
+	}
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(asString(src))
+		return nil
+	case reflect.Slice:
+		if s, ok := src.(string); ok && dv.Type().Elem().Kind() == reflect.Uint8 {
+			dv.SetBytes([]byte(s))
+			return nil
+		}
+	case reflect.Bool:
+		b, err := driver.Bool.ConvertValue(src)
+		if err != nil {
+			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %w", src, asString(src), dv.Kind(), err)
+		}
+		dv.SetBool(b.(bool))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(asString(src), 10, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %w", src, asString(src), dv.Kind(), err)
+		}
+		dv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(asString(src), 10, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %w", src, asString(src), dv.Kind(), err)
+		}
+		dv.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(asString(src), dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %w", src, asString(src), dv.Kind(), err)
+		}
+		dv.SetFloat(f)
+		return nil
+	}
+	if sv.Type().ConvertibleTo(dv.Type()) && sv.Kind() == dv.Kind() {
+		dv.Set(sv.Convert(dv.Type()))
+		return nil
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %s", src, dv.Type())
+}
+
+// asString returns the text form of a driver value.
+func asString(src any) string {
+	switch v := src.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(src)
+}
@@ -0,0 +1,197 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+type event interface {
+	EventID() int
+}
+
+type signupEvent struct {
+	ID    int    `db:"id"`
+	Kind  string `db:"kind"`
+	Email string `db:"email"`
+}
+
+func (e signupEvent) EventID() int { return e.ID }
+
+type paymentEvent struct {
+	ID     int `db:"id"`
+	Amount int `db:"amount"`
+}
+
+func (e *paymentEvent) EventID() int { return e.ID }
+
+func TestDiscriminator(t *testing.T) {
+	var schema = Schema{
+		create: `
+CREATE TABLE events (
+	id integer,
+	kind text,
+	email text NULL,
+	amount integer NULL
+);`,
+		drop: `drop table events;`,
+	}
+
+	RunWithSchemaContext(context.Background(), schema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		db.MustExecContext(ctx, "INSERT INTO events (id, kind, email) VALUES (1, 'signup', 'a@example.com')")
+		db.MustExecContext(ctx, "INSERT INTO events (id, kind, amount) VALUES (2, 'payment', 10)")
+		db.MustExecContext(ctx, "INSERT INTO events (id, kind, email) VALUES (3, 'signup', 'b@example.com')")
+
+		db = NewDb(db.DB, db.DriverName())
+		db.Mapper = reflectx.NewMapperFunc("db", strings.ToLower)
+		db.Mapper.RegisterDiscriminator(reflect.TypeFor[event](), "kind", map[string]reflect.Type{
+			"signup":  reflect.TypeFor[signupEvent](),
+			"payment": reflect.TypeFor[*paymentEvent](),
+		})
+
+		var events []event
+		if err := db.SelectContext(ctx, &events, "SELECT id, kind, email, amount FROM events ORDER BY id"); err == nil {
+			t.Error("expected an error for columns missing from the concrete type")
+		}
+		err := db.Unsafe().SelectContext(ctx, &events, "SELECT id, kind, email, amount FROM events ORDER BY id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 3 {
+			t.Fatalf("expected 3 events, got %d", len(events))
+		}
+		if e, ok := events[0].(signupEvent); !ok || e.Email != "a@example.com" || e.Kind != "signup" {
+			t.Errorf("unexpected event: %#v", events[0])
+		}
+		if e, ok := events[1].(*paymentEvent); !ok || e.Amount != 10 {
+			t.Errorf("unexpected event: %#v", events[1])
+		}
+		if events[2].EventID() != 3 {
+			t.Errorf("unexpected event: %#v", events[2])
+		}
+
+		rows, err := db.Unsafe().QueryxContext(ctx, "SELECT id, kind, email, amount FROM events ORDER BY id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var ids []int
+		for rows.Next() {
+			var e event
+			if err := rows.StructScan(&e); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, e.EventID())
+		}
+		if len(ids) != 3 || ids[1] != 2 {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+
+		// iteration picks the concrete type of each row too
+		query := "SELECT id, kind, email, amount FROM events ORDER BY id"
+		rows, err = db.Unsafe().QueryxContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = nil
+		for e, err := range Iter[event](rows) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, e.EventID())
+		}
+		if len(ids) != 3 || ids[2] != 3 {
+			t.Errorf("unexpected ids from Iter: %v", ids)
+		}
+		var kinds []string
+		for e, err := range Each[event](ctx, db.Unsafe(), query) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			kinds = append(kinds, reflect.TypeOf(e).String())
+		}
+		if strings.Join(kinds, " ") != "sqlx.signupEvent *sqlx.paymentEvent sqlx.signupEvent" {
+			t.Errorf("unexpected types from Each: %v", kinds)
+		}
+		var amount int
+		err = SelectFunc(ctx, db.Unsafe(), query, nil, func(e event) error {
+			if p, ok := e.(*paymentEvent); ok {
+				amount += p.Amount
+			}
+			return nil
+		})
+		if err != nil || amount != 10 {
+			t.Errorf("expected an amount of 10 from SelectFunc, got %d %v", amount, err)
+		}
+
+		err = db.Unsafe().SelectContext(ctx, &events, "SELECT id, 'refund' AS kind FROM events")
+		if err == nil || !strings.Contains(err.Error(), `"refund"`) {
+			t.Errorf("expected an error for an unregistered kind, got %v", err)
+		}
+		err = db.SelectContext(ctx, &events, "SELECT id FROM events")
+		if err == nil || !strings.Contains(err.Error(), "discriminator") {
+			t.Errorf("expected an error for a missing discriminator column, got %v", err)
+		}
+	})
+}
+
+func TestConvertAssign(t *testing.T) {
+	type myString string
+	var (
+		s  string
+		ms myString
+		n  int32
+		u  uint8
+		f  float64
+		b  bool
+		bs []byte
+		p  *int
+		v  any
+		ns sql.NullString
+	)
+	for _, tc := range []struct {
+		dest, src, want any
+	}{
+		{&s, []byte("abc"), "abc"},
+		{&s, int64(12), "12"},
+		{&ms, "x", myString("x")},
+		{&n, []byte("42"), int32(42)},
+		{&u, int64(7), uint8(7)},
+		{&f, "1.5", 1.5},
+		{&b, int64(1), true},
+		{&bs, "raw", []byte("raw")},
+		{&p, int64(3), 3},
+		{&v, int64(4), int64(4)},
+		{&ns, "ok", sql.NullString{String: "ok", Valid: true}},
+	} {
+		if err := convertAssign(tc.dest, tc.src); err != nil {
+			t.Errorf("%T from %#v: %v", tc.dest, tc.src, err)
+			continue
+		}
+		got := reflect.ValueOf(tc.dest).Elem().Interface()
+		if ip, ok := got.(*int); ok {
+			got = *ip
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%T from %#v: got %#v, want %#v", tc.dest, tc.src, got, tc.want)
+		}
+	}
+
+	if err := convertAssign(&p, nil); err != nil || p != nil {
+		t.Errorf("expected NULL to clear a pointer, got %v, %v", p, err)
+	}
+	if err := convertAssign(&n, nil); err == nil {
+		t.Error("expected an error for NULL into an int")
+	}
+	if err := convertAssign(&n, "1.5"); err == nil {
+		t.Error("expected an error for a float into an int")
+	}
+	src := []byte("shared")
+	if err := convertAssign(&bs, src); err != nil || &bs[0] == &src[0] {
+		t.Error("expected byte slices to be copied")
+	}
+}
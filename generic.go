@@ -0,0 +1,218 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// SelectAll executes a query using the provided QueryerContext and returns
+// every row scanned into a T.  If T is scannable, then the result set must
+// have only one column.  Otherwise, StructScan is used.  T may also be a
+// pointer to a struct, in which case each row is allocated separately.
+// The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectAll[T any](ctx context.Context, q QueryerContext, query string, args ...any) ([]T, error) {
+	var dest []T
+	if err := SelectContext(ctx, q, &dest, query, args...); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// GetOne does a QueryRow using the provided QueryerContext and returns the
+// resulting row scanned into a T.  If T is scannable, the result must only
+// have one column.  Otherwise, StructScan is used.  If T is a pointer to a
+// struct, a new value is allocated for it.  GetOne will return sql.ErrNoRows
+// like row.Scan would.
+// Any placeholder parameters are replaced with supplied args.
+func GetOne[T any](ctx context.Context, q QueryerContext, query string, args ...any) (T, error) {
+	var dest T
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer && !isScannable(t.Elem()) {
+		vp := reflect.New(t.Elem())
+		if err := GetContext(ctx, q, vp.Interface(), query, args...); err != nil {
+			return dest, err
+		}
+		return vp.Interface().(T), nil
+	}
+	if err := GetContext(ctx, q, &dest, query, args...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return dest, nil
+}
+
+// SelectMapOptions configures SelectMapWith.
+type SelectMapOptions struct {
+	// KeyColumn is the column holding the key of each row.
+	KeyColumn string
+	// Overwrite lets a row replace an earlier row with the same key, instead
+	// of failing with an error.
+	Overwrite bool
+}
+
+// SelectMap executes a query using the provided QueryerContext and returns
+// the rows scanned into V as in SelectAll, keyed by the value of keyColumn.
+// If V is scannable, the result set must have exactly two columns, the key
+// column and the value.  If V is a struct, the key column is scanned into its
+// field like any other column, and the key is read back from that field.  An
+// error is returned if two rows share a key or if a key is NULL.
+// Any placeholder parameters are replaced with supplied args.
+func SelectMap[K comparable, V any](ctx context.Context, q QueryerContext, keyColumn string, query string, args ...any) (map[K]V, error) {
+	return SelectMapWith[K, V](ctx, q, SelectMapOptions{KeyColumn: keyColumn}, query, args...)
+}
+
+// SelectMapWith is like SelectMap, with the behaviour set by opts.
+// Any placeholder parameters are replaced with supplied args.
+func SelectMapWith[K comparable, V any](ctx context.Context, q QueryerContext, opts SelectMapOptions, query string, args ...any) (map[K]V, error) {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	k := slices.Index(columns, opts.KeyColumn)
+	if k < 0 {
+		return nil, fmt.Errorf("missing key column %s in result", opts.KeyColumn)
+	}
+
+	t := reflect.TypeFor[V]()
+	scalar := t != _rowMapType && isScannable(reflectx.Deref(t))
+	if scalar && len(columns) != 2 {
+		return nil, fmt.Errorf("non-struct dest type %s needs a key and a value column, got %d columns", t.Kind(), len(columns))
+	}
+
+	result := map[K]V{}
+	for rows.Next() {
+		var key sql.Null[K]
+		var value V
+		switch {
+		case scalar:
+			values := make([]any, 2)
+			values[k], values[1-k] = &key, &value
+			err = rows.Scan(values...)
+		case t == _rowMapType:
+			value, err = scanRow[V](rows)
+			if err == nil {
+				err = key.Scan(any(value).(map[string]any)[opts.KeyColumn])
+			}
+		default:
+			value, err = scanRow[V](rows)
+			if err == nil {
+				err = scanKey(rows, reflect.ValueOf(value), opts.KeyColumn, &key)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !key.Valid {
+			return nil, fmt.Errorf("NULL key in column %s", opts.KeyColumn)
+		}
+		if _, ok := result[key.V]; ok && !opts.Overwrite {
+			return nil, fmt.Errorf("duplicate key %v in column %s", key.V, opts.KeyColumn)
+		}
+		result[key.V] = value
+	}
+	return result, rows.Err()
+}
+
+// scanKey scans the field of the struct v which the key column maps to into key.
+func scanKey[K any](rows *Rows, v reflect.Value, column string, key *sql.Null[K]) error {
+	v = reflect.Indirect(v)
+	fi, ok := rows.Mapper.TypeMap(v.Type()).Names[column]
+	if !ok {
+		return fmt.Errorf("key column %s does not map to a field of %s", column, v.Type())
+	}
+	for _, i := range fi.Index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return key.Scan(nil)
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return key.Scan(nil)
+		}
+		v = v.Elem()
+	}
+	src := v.Interface()
+	if valuer, ok := src.(driver.Valuer); ok {
+		var err error
+		if src, err = valuer.Value(); err != nil {
+			return err
+		}
+	}
+	return key.Scan(src)
+}
+
+// Get2 does a QueryRow using the provided QueryerContext and scans the
+// resulting row into an A and a B, as GetContext does with Dests.  Get2 will
+// return sql.ErrNoRows like row.Scan would.
+// Any placeholder parameters are replaced with supplied args.
+func Get2[A, B any](ctx context.Context, q QueryerContext, query string, args ...any) (A, B, error) {
+	var a A
+	var b B
+	if err := GetContext(ctx, q, Dests(&a, &b), query, args...); err != nil {
+		var za A
+		var zb B
+		return za, zb, err
+	}
+	return a, b, nil
+}
+
+// Get3 does a QueryRow using the provided QueryerContext and scans the
+// resulting row into an A, a B and a C, as GetContext does with Dests.  Get3
+// will return sql.ErrNoRows like row.Scan would.
+// Any placeholder parameters are replaced with supplied args.
+func Get3[A, B, C any](ctx context.Context, q QueryerContext, query string, args ...any) (A, B, C, error) {
+	var a A
+	var b B
+	var c C
+	if err := GetContext(ctx, q, Dests(&a, &b, &c), query, args...); err != nil {
+		var za A
+		var zb B
+		var zc C
+		return za, zb, zc, err
+	}
+	return a, b, c, nil
+}
+
+// SelectFunc executes a query using the provided QueryerContext and calls fn
+// with every row scanned into a new T, as in SelectAll, without holding the
+// results in memory.  Iteration stops at the first error returned by fn,
+// which is returned.  The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectFunc[T any](ctx context.Context, q QueryerContext, query string, args []any, fn func(T) error) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+
+	for rows.Next() {
+		value, err := scanRow[T](rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(value); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
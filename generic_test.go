@@ -0,0 +1,232 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestSelectAllGetOne(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		people, err := SelectAll[Person](ctx, db, "SELECT * FROM person ORDER BY first_name ASC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 || people[0].FirstName != "Jason" || people[1].FirstName != "John" {
+			t.Errorf("unexpected people: %#v", people)
+		}
+
+		ptrs, err := SelectAll[*Person](ctx, db, "SELECT * FROM person ORDER BY first_name ASC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ptrs) != 2 || ptrs[1].LastName != "Doe" {
+			t.Errorf("unexpected people: %#v", ptrs)
+		}
+
+		names, err := SelectAll[string](ctx, db, "SELECT first_name FROM person ORDER BY first_name ASC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 2 || names[0] != "Jason" {
+			t.Errorf("unexpected names: %#v", names)
+		}
+
+		none, err := SelectAll[Person](ctx, db, "SELECT * FROM person WHERE first_name = 'Jack'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if none != nil {
+			t.Errorf("expected nil slice, got %#v", none)
+		}
+
+		p, err := GetOne[Person](ctx, db, db.Rebind("SELECT * FROM person WHERE first_name = ?"), "John")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.LastName != "Doe" {
+			t.Errorf("expected Doe, got %s", p.LastName)
+		}
+
+		pp, err := GetOne[*Person](ctx, db, db.Rebind("SELECT * FROM person WHERE first_name = ?"), "Jason")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pp == nil || pp.LastName != "Moiron" {
+			t.Errorf("expected Moiron, got %#v", pp)
+		}
+
+		count, err := GetOne[int](ctx, db, "SELECT count(*) FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2, got %d", count)
+		}
+
+		pp, err = GetOne[*Person](ctx, db, "SELECT * FROM person WHERE first_name = 'Jack'")
+		if err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+		if pp != nil {
+			t.Errorf("expected nil on error, got %#v", pp)
+		}
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		if _, err = GetOne[Person](ctx, tx, "SELECT * FROM person LIMIT 1"); err != nil {
+			t.Error(err)
+		}
+
+		stmt, err := tx.PreparexContext(ctx, tx.Rebind("SELECT * FROM person WHERE first_name = ?"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		people, err = SelectAll[Person](ctx, stmt.AsQueryer(), "", "Jason")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 1 || people[0].LastName != "Moiron" {
+			t.Errorf("unexpected people: %#v", people)
+		}
+	})
+}
+
+func TestSelectMap(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		places, err := SelectMap[int64, Place](ctx, db, "telcode", "SELECT * FROM place")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(places) != 3 || places[852].Country != "Hong Kong" || places[852].TelCode != 852 {
+			t.Errorf("unexpected places: %#v", places)
+		}
+
+		ptrs, err := SelectMap[string, *Place](ctx, db, "country", "SELECT * FROM place")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := ptrs["Singapore"]; p == nil || p.TelCode != 65 {
+			t.Errorf("unexpected places: %#v", ptrs)
+		}
+
+		codes, err := SelectMap[string, int](ctx, db, "country", "SELECT country, telcode FROM place")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if codes["United States"] != 1 {
+			t.Errorf("unexpected codes: %#v", codes)
+		}
+
+		rows, err := SelectMap[int, map[string]any](ctx, db, "telcode", "SELECT telcode, country FROM place")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 3 || rows[65] == nil {
+			t.Errorf("unexpected rows: %#v", rows)
+		}
+
+		_, err = SelectMap[string, Person](ctx, db, "last_name", "SELECT * FROM person UNION ALL SELECT * FROM person")
+		if err == nil {
+			t.Error("expected an error for duplicate keys")
+		}
+		people, err := SelectMapWith[string, Person](ctx, db, SelectMapOptions{KeyColumn: "last_name", Overwrite: true}, "SELECT * FROM person UNION ALL SELECT * FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 {
+			t.Errorf("expected 2 people, got %d", len(people))
+		}
+
+		_, err = SelectMap[string, Place](ctx, db, "city", "SELECT * FROM place")
+		if err == nil {
+			t.Error("expected an error for a NULL key")
+		}
+		_, err = SelectMap[string, Place](ctx, db, "nope", "SELECT * FROM place")
+		if err == nil {
+			t.Error("expected an error for a missing key column")
+		}
+
+		var maps []map[string]any
+		if err = db.SelectContext(ctx, &maps, "SELECT * FROM person ORDER BY first_name"); err != nil {
+			t.Fatal(err)
+		}
+		if len(maps) != 2 || len(maps[0]) != 4 {
+			t.Errorf("unexpected maps: %#v", maps)
+		}
+		if err = StructScan(nil, &maps); err == nil {
+			t.Error("expected StructScan to reject maps")
+		}
+	})
+}
+
+func TestDests(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		var count int
+		var first string
+		err := db.GetContext(ctx, Dests(&count, &first), "SELECT count(*), min(first_name) FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 || first != "Jason" {
+			t.Errorf("unexpected values: %d, %s", count, first)
+		}
+
+		var p Person
+		var code int
+		err = db.GetContext(ctx, Dests(&p, &code), "SELECT person.*, telcode FROM person, place WHERE first_name = 'John' AND country = 'Singapore'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.LastName != "Doe" || code != 65 {
+			t.Errorf("unexpected values: %#v, %d", p, code)
+		}
+
+		err = db.GetContext(ctx, Dests(&count, &first), "SELECT count(*), min(first_name), 1 FROM person")
+		if err == nil {
+			t.Error("expected an error for an unconsumed column")
+		}
+		err = db.Unsafe().GetContext(ctx, Dests(&count, &first), "SELECT count(*), min(first_name), 1 FROM person")
+		if err != nil {
+			t.Errorf("expected unsafe to ignore extra columns, got %v", err)
+		}
+		err = db.GetContext(ctx, Dests(&count, &first), "SELECT count(*) FROM person")
+		if err == nil {
+			t.Error("expected an error for a missing column")
+		}
+		err = db.GetContext(ctx, Dests(&count, &first), "SELECT 1, first_name FROM person WHERE first_name = 'Jack'")
+		if err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+
+		n, name, err := Get2[int64, string](ctx, db, "SELECT count(*), max(first_name) FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 || name != "John" {
+			t.Errorf("unexpected values: %d, %s", n, name)
+		}
+
+		pp, place, last, err := Get3[*Person, Place, string](ctx, db, "SELECT first_name, last_name, email, added_at, country, city, telcode, last_name FROM person, place WHERE first_name = 'Jason' AND telcode = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pp == nil || pp.FirstName != "Jason" || place.Country != "United States" || last != "Moiron" {
+			t.Errorf("unexpected values: %#v, %#v, %s", pp, place, last)
+		}
+
+		_, _, err = Get2[int, string](ctx, db, "SELECT 1, first_name FROM person WHERE first_name = 'Jack'")
+		if err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,312 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// SelectGrouped executes a query using the provided Queryer and scans the
+// rows into dest, which must be a slice of structs, as GroupScan does.
+// The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectGrouped(q Queryer, dest any, query string, args ...any) error {
+	rows, err := q.Queryx(query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanGrouped(rows, dest)
+}
+
+// SelectGroupedContext executes a query using the provided QueryerContext and
+// scans the rows into dest, which must be a slice of structs, as GroupScan
+// does.  The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectGroupedContext(ctx context.Context, q QueryerContext, dest any, query string, args ...any) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanGrouped(rows, dest)
+}
+
+// GroupScan scans the rows of a one-to-many join into dest, which must be a
+// slice of structs.  Rows are grouped by the struct fields tagged with the
+// `pk` option, so that each distinct key produces a single element.  Slice of
+// struct fields collect the columns prefixed with their name:
+//
+//	type Order struct {
+//		ID    int    `db:"id,pk"`
+//		Items []Item `db:"item"`
+//	}
+//
+// will scan `item.sku` into the Item.SKU field of a new element of Items.
+// Child rows whose columns are all NULL, as produced by a LEFT JOIN with no
+// match, are skipped.  If the child struct has fields tagged with `pk`, rows
+// repeating a child key within the same parent are skipped as well, which
+// allows several collections to be filled from the same query.
+func GroupScan(rows rowsi, dest any) error {
+	return scanGrouped(rows, dest)
+}
+
+// A groupedCollection is a slice of struct field filled from prefixed columns.
+type groupedCollection struct {
+	field   *reflectx.FieldInfo
+	elem    reflect.Type
+	isPtr   bool
+	columns []int
+	fields  [][]int
+	keys    [][]int
+	seen    map[any]bool
+}
+
+func scanGrouped(rows rowsi, dest any) error {
+	value := reflect.ValueOf(dest)
+
+	if value.Kind() != reflect.Pointer {
+		return errors.New("must pass a pointer, not a value, to GroupScan destination")
+	}
+	if value.IsNil() {
+		return errors.New("nil pointer passed to GroupScan destination")
+	}
+	direct := reflect.Indirect(value)
+
+	slice, err := baseType(value.Type(), reflect.Slice)
+	if err != nil {
+		return err
+	}
+	direct.SetLen(0)
+
+	isPtr := slice.Elem().Kind() == reflect.Pointer
+	base := reflectx.Deref(slice.Elem())
+	if isScannable(base) {
+		return structOnlyError(base)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	m := rowsMapper(rows)
+	tm := m.TypeMap(base)
+
+	pks := tagged(tm, "pk")
+	if len(pks) == 0 {
+		return fmt.Errorf("no field of %s is tagged with the pk option", base)
+	}
+
+	var collections []*groupedCollection
+	for _, fi := range tm.Index {
+		if fi.Name == "" || fi.Embedded {
+			continue
+		}
+		ft := fi.Field.Type
+		if ft.Kind() != reflect.Slice || isScannable(reflectx.Deref(ft.Elem())) {
+			continue
+		}
+		elem := reflectx.Deref(ft.Elem())
+		collections = append(collections, &groupedCollection{
+			field: fi,
+			elem:  elem,
+			isPtr: ft.Elem().Kind() == reflect.Pointer,
+			keys:  indexes(tagged(m.TypeMap(elem), "pk")),
+		})
+	}
+
+	fields := make([][]int, len(columns))
+	values := make([]any, len(columns))
+	holders := make([]reflect.Value, len(columns))
+
+ColumnLoop:
+	for i, column := range columns {
+		if fi, ok := tm.Names[column]; ok && !isCollection(collections, fi) {
+			fields[i] = fi.Index
+			continue
+		}
+		for _, c := range collections {
+			name, ok := strings.CutPrefix(column, c.field.Path+".")
+			if !ok {
+				continue
+			}
+			cfi, ok := m.TypeMap(c.elem).Names[name]
+			if !ok {
+				break
+			}
+			c.columns = append(c.columns, i)
+			c.fields = append(c.fields, cfi.Index)
+			holders[i] = reflect.New(reflect.PointerTo(cfi.Field.Type))
+			values[i] = holders[i].Interface()
+			continue ColumnLoop
+		}
+		if !isUnsafe(rows) {
+			return fmt.Errorf("missing destination name %s in %T", column, dest)
+		}
+		values[i] = new(any)
+	}
+
+	for _, pk := range pks {
+		if !covered(fields, pk.Index) {
+			return fmt.Errorf("missing pk column %s in result for %T", pk.Path, dest)
+		}
+	}
+	keys := indexes(pks)
+
+	groups := map[any]int{}
+
+	for rows.Next() {
+		vp := reflect.New(base)
+		v := reflect.Indirect(vp)
+		for i, traversal := range fields {
+			if traversal != nil {
+				values[i] = reflectx.FieldByIndexes(v, traversal).Addr().Interface()
+			}
+		}
+
+		if err = rows.Scan(values...); err != nil {
+			return err
+		}
+
+		key := groupKey(v, keys)
+		n, ok := groups[key]
+		if !ok {
+			n = direct.Len()
+			groups[key] = n
+			if isPtr {
+				direct.Set(reflect.Append(direct, vp))
+			} else {
+				direct.Set(reflect.Append(direct, v))
+			}
+		}
+		parent := reflect.Indirect(direct.Index(n))
+
+		for _, c := range collections {
+			c.appendRow(parent, n, holders)
+		}
+	}
+
+	return rows.Err()
+}
+
+// appendRow builds an element of the collection from the current row's
+// holders and appends it to the collection field of parent, which is the
+// nth element of the destination.
+func (c *groupedCollection) appendRow(parent reflect.Value, n int, holders []reflect.Value) {
+	null := true
+	for _, i := range c.columns {
+		if !holders[i].Elem().IsNil() {
+			null = false
+			break
+		}
+	}
+	if null {
+		return
+	}
+
+	ep := reflect.New(c.elem)
+	e := ep.Elem()
+	for j, i := range c.columns {
+		if h := holders[i].Elem(); !h.IsNil() {
+			reflectx.FieldByIndexes(e, c.fields[j]).Set(h.Elem())
+		}
+	}
+
+	if len(c.keys) > 0 {
+		key := [2]any{n, groupKey(e, c.keys)}
+		if c.seen == nil {
+			c.seen = map[any]bool{}
+		}
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+	}
+
+	f := reflectx.FieldByIndexes(parent, c.field.Index)
+	if c.isPtr {
+		f.Set(reflect.Append(f, ep))
+	} else {
+		f.Set(reflect.Append(f, e))
+	}
+}
+
+// tagged returns the fields of tm which carry the option opt.
+func tagged(tm *reflectx.StructMap, opt string) []*reflectx.FieldInfo {
+	var fis []*reflectx.FieldInfo
+	for _, fi := range tm.Index {
+		if _, ok := fi.Options[opt]; ok {
+			fis = append(fis, fi)
+		}
+	}
+	return fis
+}
+
+func indexes(fis []*reflectx.FieldInfo) [][]int {
+	r := make([][]int, len(fis))
+	for i, fi := range fis {
+		r[i] = fi.Index
+	}
+	return r
+}
+
+func isCollection(collections []*groupedCollection, fi *reflectx.FieldInfo) bool {
+	for _, c := range collections {
+		if c.field == fi {
+			return true
+		}
+	}
+	return false
+}
+
+// covered reports whether traversal is one of the traversals in fields.
+func covered(fields [][]int, traversal []int) bool {
+	return slices.ContainsFunc(fields, func(f []int) bool {
+		return slices.Equal(f, traversal)
+	})
+}
+
+// groupKey returns a comparable key made of the values of the fields of v
+// at the given traversals.  Composite keys are arrays of the field values, so
+// that they compare field by field.
+func groupKey(v reflect.Value, traversals [][]int) any {
+	if len(traversals) == 1 {
+		return keyValue(reflectx.FieldByIndexesReadOnly(v, traversals[0]))
+	}
+	key := reflect.New(reflect.ArrayOf(len(traversals), reflect.TypeFor[any]())).Elem()
+	for i, t := range traversals {
+		if kv := keyValue(reflectx.FieldByIndexesReadOnly(v, t)); kv != nil {
+			key.Index(i).Set(reflect.ValueOf(kv))
+		}
+	}
+	return key.Interface()
+}
+
+// keyValue returns the value of f as a comparable map key.  Pointers are
+// followed, a nil one giving a nil key, and byte slices are keyed by their
+// contents.
+func keyValue(f reflect.Value) any {
+	for f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return nil
+		}
+		f = f.Elem()
+	}
+	switch {
+	case !f.IsValid():
+		return nil
+	case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Uint8:
+		return string(f.Bytes())
+	case f.Comparable():
+		return f.Interface()
+	}
+	return fmt.Sprintf("%#v", f.Interface())
+}
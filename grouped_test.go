@@ -0,0 +1,152 @@
+package sqlx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var groupedSchema = Schema{
+	create: `
+CREATE TABLE orders (
+	id integer,
+	customer text
+);
+
+CREATE TABLE order_items (
+	order_id integer,
+	sku text,
+	qty integer
+);
+
+CREATE TABLE order_notes (
+	order_id integer,
+	id integer,
+	body text
+);
+`,
+	drop: `
+drop table orders;
+drop table order_items;
+drop table order_notes;
+`,
+}
+
+// quoteAliases replaces double quoted aliases with backticks for MySQL.
+func quoteAliases(db *DB, query string) string {
+	if db.DriverName() == "mysql" {
+		return strings.ReplaceAll(query, `"`, "`")
+	}
+	return query
+}
+
+func TestSelectGrouped(t *testing.T) {
+	RunWithSchemaContext(context.Background(), groupedSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		for _, q := range []string{
+			"INSERT INTO orders (id, customer) VALUES (1, 'ann'), (2, 'bob'), (3, 'cid')",
+			"INSERT INTO order_items (order_id, sku, qty) VALUES (1, 'a', 1), (1, 'b', 2), (2, 'c', 3)",
+			"INSERT INTO order_notes (order_id, id, body) VALUES (1, 1, 'gift'), (1, 2, 'rush')",
+		} {
+			db.MustExecContext(ctx, q)
+		}
+
+		type Item struct {
+			SKU string `db:"sku"`
+			Qty int    `db:"qty"`
+		}
+		type Order struct {
+			ID       int    `db:"id,pk"`
+			Customer string `db:"customer"`
+			Items    []Item `db:"item"`
+		}
+
+		var orders []Order
+		err := SelectGroupedContext(ctx, db, &orders, quoteAliases(db, `
+			SELECT o.id, o.customer, i.sku AS "item.sku", i.qty AS "item.qty"
+			FROM orders o LEFT JOIN order_items i ON i.order_id = o.id
+			ORDER BY o.id, i.sku`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(orders) != 3 {
+			t.Fatalf("expected 3 orders, got %d: %#v", len(orders), orders)
+		}
+		if len(orders[0].Items) != 2 || orders[0].Items[1].SKU != "b" || orders[0].Items[1].Qty != 2 {
+			t.Errorf("unexpected items for order 1: %#v", orders[0].Items)
+		}
+		if len(orders[1].Items) != 1 || orders[1].Customer != "bob" {
+			t.Errorf("unexpected order 2: %#v", orders[1])
+		}
+		if orders[2].Items != nil {
+			t.Errorf("expected no items for an order without matches, got %#v", orders[2].Items)
+		}
+
+		type Note struct {
+			ID   int    `db:"id,pk"`
+			Body string `db:"body"`
+		}
+		type Detail struct {
+			ID    int     `db:"id,pk"`
+			Items []*Item `db:"item"`
+			Notes []Note  `db:"note"`
+		}
+
+		var details []*Detail
+		err = SelectGrouped(db, &details, quoteAliases(db, `
+			SELECT o.id, i.sku AS "item.sku", i.qty AS "item.qty", n.id AS "note.id", n.body AS "note.body"
+			FROM orders o
+			JOIN order_items i ON i.order_id = o.id
+			JOIN order_notes n ON n.order_id = o.id
+			ORDER BY o.id, i.sku, n.id`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(details) != 1 {
+			t.Fatalf("expected 1 order, got %d", len(details))
+		}
+		if len(details[0].Notes) != 2 {
+			t.Errorf("expected notes to be deduplicated by pk, got %#v", details[0].Notes)
+		}
+		if len(details[0].Items) != 4 {
+			t.Errorf("expected items without a pk to be kept per row, got %d", len(details[0].Items))
+		}
+
+		err = SelectGroupedContext(ctx, db, &orders, quoteAliases(db, `SELECT o.id, o.customer, 1 AS "item.nope" FROM orders o`))
+		if err == nil {
+			t.Error("expected an error for an unknown child column")
+		}
+		err = SelectGroupedContext(ctx, db, &orders, `SELECT customer FROM orders`)
+		if err == nil {
+			t.Error("expected an error when the pk column is missing")
+		}
+
+		var noKey []Item
+		err = SelectGroupedContext(ctx, db, &noKey, `SELECT sku, qty FROM order_items`)
+		if err == nil {
+			t.Error("expected an error for a struct without a pk field")
+		}
+	})
+}
+
+func TestGroupKey(t *testing.T) {
+	type key struct {
+		ID   *int   `db:"id,pk"`
+		Code []byte `db:"code,pk"`
+	}
+	one, another := 1, 1
+	a := reflect.ValueOf(key{ID: &one, Code: []byte("x")})
+	b := reflect.ValueOf(key{ID: &another, Code: []byte("x")})
+	c := reflect.ValueOf(key{Code: []byte("x")})
+	traversals := [][]int{{0}, {1}}
+
+	if groupKey(a, traversals) != groupKey(b, traversals) {
+		t.Error("expected keys with equal values to be equal")
+	}
+	if groupKey(a, traversals) == groupKey(c, traversals) {
+		t.Error("expected a nil key field to differ from a set one")
+	}
+	if groupKey(a, traversals[1:]) != groupKey(c, traversals[1:]) {
+		t.Error("expected byte slice keys to compare by contents")
+	}
+}
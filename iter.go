@@ -0,0 +1,108 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Each executes a query using the provided QueryerContext and returns an
+// iterator over the resulting rows, each scanned into a T as in SelectAll.
+// The query is run when iteration begins.  Any error, whether from the query,
+// a scan or the rows themselves, is yielded once and ends the iteration.  The
+// rows are closed when the loop finishes, including when it is exited early.
+// Any placeholder parameters are replaced with supplied args.
+func Each[T any](ctx context.Context, q QueryerContext, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		rows, err := q.QueryxContext(ctx, query, args...)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for v, err := range Iter[T](rows) {
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// Iter returns an iterator over r, scanning each row into a T.  If T is
+// scannable, the result set must have only one column.  Otherwise, StructScan
+// is used, which caches the column to field mapping on r.  An interface T with
+// a discriminator registered on the mapper gets the concrete type selected by
+// each row.  The rows are closed
+// when the loop finishes, including when it is exited early.
+func Iter[T any](r *Rows) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for r, err := range r.All() {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			v, err := scanRow[T](r)
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator which advances r one row at a time, yielding r
+// itself so that the current row can be read with Scan, StructScan, MapScan
+// or SliceScan.  An error from the rows is yielded last.  The rows are closed
+// when the loop finishes, including when it is exited early.
+func (r *Rows) All() iter.Seq2[*Rows, error] {
+	return func(yield func(*Rows, error) bool) {
+		defer r.Close()
+		for r.Next() {
+			if !yield(r, nil) {
+				return
+			}
+		}
+		if err := r.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// scanRow scans the current row of r into a new T.
+func scanRow[T any](r *Rows) (T, error) {
+	var dest T
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer && !isScannable(t.Elem()) {
+		vp := reflect.New(t.Elem())
+		if err := r.StructScan(vp.Interface()); err != nil {
+			return dest, err
+		}
+		return vp.Interface().(T), nil
+	}
+	if t == _rowMapType {
+		m := map[string]any{}
+		err := MapScan(r, m)
+		return any(m).(T), err
+	}
+	// interfaces with a discriminator get a concrete type per row
+	if t.Kind() == reflect.Interface && r.Mapper != nil {
+		if _, ok := r.Mapper.Discriminator(t); ok {
+			err := r.StructScan(&dest)
+			return dest, err
+		}
+	}
+	if isScannable(t) {
+		columns, err := r.Columns()
+		if err != nil {
+			return dest, err
+		}
+		if len(columns) > 1 {
+			return dest, fmt.Errorf("non-struct dest type %s with >1 columns (%d)", t.Kind(), len(columns))
+		}
+		err = r.Scan(&dest)
+		return dest, err
+	}
+	err := r.StructScan(&dest)
+	return dest, err
+}
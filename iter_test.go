@@ -0,0 +1,172 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEach(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		var names []string
+		for p, err := range Each[Person](ctx, db, "SELECT * FROM person ORDER BY first_name ASC") {
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, p.FirstName)
+		}
+		if len(names) != 2 || names[0] != "Jason" || names[1] != "John" {
+			t.Errorf("unexpected names: %#v", names)
+		}
+
+		for p, err := range Each[*Place](ctx, db, "SELECT * FROM place ORDER BY telcode ASC") {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.TelCode != 1 {
+				t.Errorf("expected telcode 1, got %d", p.TelCode)
+			}
+			break
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("expected connection to be released after break, %d in use", inUse)
+		}
+
+		total := 0
+		for code, err := range Each[int](ctx, db, "SELECT telcode FROM place") {
+			if err != nil {
+				t.Fatal(err)
+			}
+			total += code
+		}
+		if total != 1+852+65 {
+			t.Errorf("unexpected telcode total %d", total)
+		}
+
+		var seen bool
+		for _, err := range Each[Person](ctx, db, "SELECT * FROM nonexistent") {
+			if err == nil {
+				t.Error("expected an error for a missing table")
+			}
+			seen = true
+		}
+		if !seen {
+			t.Error("expected the query error to be yielded")
+		}
+
+		for _, err := range Each[int](ctx, db, "SELECT telcode, country FROM place") {
+			if err == nil {
+				t.Error("expected an error scanning 2 columns into an int")
+			}
+		}
+	})
+}
+
+func TestRowsAll(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		rows, err := db.QueryxContext(ctx, "SELECT * FROM person ORDER BY first_name ASC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for r, err := range rows.All() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p Person
+			if err := r.StructScan(&p); err != nil {
+				t.Fatal(err)
+			}
+			count++
+		}
+		if count != 2 {
+			t.Errorf("expected 2 rows, got %d", count)
+		}
+		if rows.Next() {
+			t.Error("expected rows to be closed")
+		}
+
+		rows, err = db.QueryxContext(ctx, "SELECT * FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for p, err := range Iter[Person](rows) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.FirstName == "" {
+				t.Error("expected a first name")
+			}
+			break
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("expected connection to be released after break, %d in use", inUse)
+		}
+	})
+}
+
+func TestSelectEach(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		var names []string
+		err := SelectFunc(ctx, db, "SELECT * FROM person ORDER BY first_name ASC", nil, func(p Person) error {
+			names = append(names, p.FirstName)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 2 || names[0] != "Jason" || names[1] != "John" {
+			t.Errorf("unexpected names: %#v", names)
+		}
+
+		stop := errors.New("stop")
+		calls := 0
+		err = SelectFunc(ctx, db, db.Rebind("SELECT telcode FROM place WHERE telcode > ?"), []any{0}, func(code int) error {
+			calls++
+			return stop
+		})
+		if err != stop || calls != 1 {
+			t.Errorf("expected iteration to stop with the callback error, got %v after %d calls", err, calls)
+		}
+		if n := db.Stats().InUse; n != 0 {
+			t.Errorf("expected the rows to be closed, %d connections in use", n)
+		}
+
+		var p Place
+		var countries []string
+		err = SelectEachContext(ctx, db, &p, func() error {
+			countries = append(countries, p.Country)
+			return nil
+		}, "SELECT * FROM place ORDER BY telcode ASC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(countries) != 3 || countries[0] != "United States" {
+			t.Errorf("unexpected countries: %#v", countries)
+		}
+
+		var name string
+		calls = 0
+		err = SelectEach(db, &name, func() error {
+			calls++
+			if name == "Jason" {
+				return stop
+			}
+			return nil
+		}, "SELECT first_name FROM person ORDER BY first_name ASC")
+		if err != stop || calls != 1 {
+			t.Errorf("expected iteration to stop with the callback error, got %v after %d calls", err, calls)
+		}
+
+		err = SelectEach(db, p, func() error { return nil }, "SELECT * FROM place")
+		if err == nil {
+			t.Error("expected an error for a non-pointer destination")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package sqlx
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// decodeJSON replaces the value of f with the JSON document in data.  It is
+// used for fields tagged with the `json` option, such as `db:"items,json"`,
+// which are scanned from columns holding a JSON document, as produced by
+// json_agg in Postgres or json_group_array in SQLite.  An empty document
+// leaves f at its zero value.
+func decodeJSON(data []byte, f reflect.Value) error {
+	f.SetZero()
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, f.Addr().Interface())
+}
+
+// encodeJSON returns v, the value of a field tagged with the `json` option,
+// encoded as a JSON document, or nil for nil pointers, slices and maps so that
+// they are bound as NULL.
+func encodeJSON(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
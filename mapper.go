@@ -19,7 +19,19 @@ func mapperFor(i any) *reflectx.Mapper {
 		return i.Mapper
 	case *Tx:
 		return i.Mapper
+	case Conn:
+		return i.Mapper
+	case *Conn:
+		return i.Mapper
 	default:
 		return mapper()
 	}
 }
+
+// rowsMapper returns the mapper of rows if it is an sqlx.Rows, or the default.
+func rowsMapper(rows rowsi) *reflectx.Mapper {
+	if r, ok := rows.(*Rows); ok {
+		return r.Mapper
+	}
+	return mapper()
+}
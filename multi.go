@@ -0,0 +1,72 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// SelectMulti executes a query returning several result sets, as stored
+// procedures and batched statements do, and scans each set into the matching
+// element of dests.  Slice destinations get every row of their set, as with
+// Select.  Other destinations get the first row of their set, as with Get,
+// and sql.ErrNoRows is returned if it is empty.  An error is returned if the
+// query returns fewer result sets than dests.  The *sql.Rows are closed
+// automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectMulti(ctx context.Context, q QueryerContext, dests []any, query string, args ...any) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+
+	for i, dest := range dests {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("expected %d result sets, got %d", len(dests), i)
+		}
+		if err := rows.scanSet(dest); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanSet scans the current result set into dest, taking every row if dest
+// is a pointer to a slice and the first one otherwise.
+func (r *Rows) scanSet(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer {
+		return ErrMustPassAPointerToStructScan
+	}
+	t := reflectx.Deref(v.Type())
+	// byte slices are scanned whole, like any other scannable value
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		return scanAll(r, dest, false)
+	}
+
+	if !r.Next() {
+		if err := r.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if !isScannable(t) {
+		return r.StructScan(dest)
+	}
+	columns, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) > 1 {
+		return fmt.Errorf("scannable dest type %s with >1 columns (%d) in result", t.Kind(), len(columns))
+	}
+	return r.Scan(dest)
+}
@@ -0,0 +1,75 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestSelectMulti(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		var people []Person
+		var total int
+		err := SelectMulti(ctx, db, []any{&people}, "SELECT * FROM person ORDER BY first_name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 || people[0].FirstName != "Jason" {
+			t.Errorf("unexpected people: %#v", people)
+		}
+		var jason Person
+		if err = SelectMulti(ctx, db, []any{&jason}, "SELECT * FROM person ORDER BY first_name"); err != nil {
+			t.Fatal(err)
+		}
+		if jason.LastName != "Moiron" {
+			t.Errorf("unexpected person: %#v", jason)
+		}
+		err = SelectMulti(ctx, db, []any{&total}, "SELECT count(*) FROM person WHERE first_name = 'Jack' GROUP BY first_name")
+		if err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+		err = SelectMulti(ctx, db, []any{&people, &total}, "SELECT * FROM person")
+		if err == nil {
+			t.Error("expected an error for a missing result set")
+		}
+
+		// sqlite3 only returns the last result set, and mysql needs the
+		// multiStatements option
+		if db.DriverName() != "postgres" {
+			return
+		}
+
+		var places []*Place
+		err = SelectMulti(ctx, db, []any{&people, &places, &total},
+			"SELECT * FROM person ORDER BY first_name; SELECT * FROM place ORDER BY telcode; SELECT count(*) FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 || len(places) != 3 || places[0].TelCode != 1 || total != 2 {
+			t.Errorf("unexpected results: %#v, %#v, %d", people, places, total)
+		}
+
+		rows, err := db.QueryxContext(ctx, "SELECT * FROM person; SELECT * FROM place")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var p Person
+			if err := rows.StructScan(&p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !rows.NextResultSet() {
+			t.Fatal("expected a second result set")
+		}
+		for rows.Next() {
+			var p Place
+			if err := rows.StructScan(&p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
@@ -13,12 +13,13 @@ package sqlx
 //
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
-	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/i9si-sistemas/sqlx/binder"
@@ -31,31 +32,44 @@ type NamedStmt struct {
 	Params      []string
 	QueryString string
 	Stmt        *Stmt
+	// shapes holds the statements prepared for slice arguments.
+	shapes *namedShapes
+	// optional makes all parameters optional, and defaults holds the values
+	// of missing optional parameters, as for the DB it was prepared on.
+	optional bool
+	defaults map[string]any
 }
 
-// Close closes the named statement.
+// Close closes the named statement, along with any statement prepared to bind
+// slice arguments.
 func (n *NamedStmt) Close() error {
-	return n.Stmt.Close()
+	err := n.Stmt.Close()
+	if n.shapes != nil {
+		err = errors.Join(err, n.shapes.close())
+	}
+	return err
 }
 
 // Exec executes a named statement using the struct passed.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) Exec(arg any) (sql.Result, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(context.Background(), arg)
 	if err != nil {
 		return *new(sql.Result), err
 	}
-	return n.Stmt.Exec(args...)
+	defer release()
+	return stmt.Exec(args...)
 }
 
 // Query executes a named statement using the struct argument, returning rows.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) Query(arg any) (*sql.Rows, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(context.Background(), arg)
 	if err != nil {
 		return nil, err
 	}
-	return n.Stmt.Query(args...)
+	defer release()
+	return stmt.Query(args...)
 }
 
 // QueryRow executes a named statement against the database.  Because sqlx cannot
@@ -63,11 +77,12 @@ func (n *NamedStmt) Query(arg any) (*sql.Rows, error) {
 // returns a *sqlx.Row instead.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) QueryRow(arg any) *Row {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(context.Background(), arg)
 	if err != nil {
 		return &Row{err: err}
 	}
-	return n.Stmt.QueryRowx(args...)
+	defer release()
+	return stmt.QueryRowx(args...)
 }
 
 // MustExec execs a NamedStmt, panicing on error
@@ -87,7 +102,7 @@ func (n *NamedStmt) Queryx(arg any) (*Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, Mapper: n.Stmt.Mapper, unsafe: isUnsafe(n)}, err
+	return &Rows{Rows: r, Mapper: n.Stmt.Mapper, unsafe: isUnsafe(n), strict: isStrict(n), nullZero: isNullTolerant(n)}, err
 }
 
 // QueryRowx this NamedStmt.  Because of limitations with QueryRow, this is
@@ -118,9 +133,9 @@ func (n *NamedStmt) Get(dest any, arg any) error {
 
 // Unsafe creates an unsafe version of the NamedStmt
 func (n *NamedStmt) Unsafe() *NamedStmt {
-	r := &NamedStmt{Params: n.Params, Stmt: n.Stmt, QueryString: n.QueryString}
+	r := *n
 	r.Stmt.unsafe = true
-	return r
+	return &r
 }
 
 // A union interface of preparer and binder, required to be able to prepare
@@ -132,18 +147,24 @@ type namedPreparer interface {
 
 func prepareNamed(p namedPreparer, query string) (*NamedStmt, error) {
 	bindType := binder.Default.Type(p.DriverName())
-	q, args, err := compileNamedQuery([]byte(query), bindType)
+	parsed, err := parseNamedQuery(query, bindType)
 	if err != nil {
 		return nil, err
 	}
+	q := parsed.render(bindType, nil)
 	stmt, err := Preparex(p, q)
 	if err != nil {
 		return nil, err
 	}
 	return &NamedStmt{
 		QueryString: q,
-		Params:      args,
+		Params:      parsed.names,
 		Stmt:        stmt,
+		shapes: &namedShapes{query: parsed, bindType: bindType, prepare: func(_ context.Context, q string) (*Stmt, error) {
+			return Preparex(p, q)
+		}},
+		optional: isOptionalNamed(p),
+		defaults: namedDefaultsFor(p),
 	}, nil
 }
 
@@ -161,17 +182,26 @@ func convertMapStringInterface(v any) (map[string]any, bool) {
 
 }
 
-func bindAnyArgs(names []string, arg any, m *reflectx.Mapper) ([]any, error) {
+// bindAnyArgs binds arg to names, the ones marked in optional being allowed to
+// be missing from arg.
+func bindAnyArgs(names []string, optional []bool, arg any, m *reflectx.Mapper) ([]any, error) {
+	arg, opt := unwrapOptional(arg, names, optional)
+	if merged, ok := arg.(MergedArgs); ok {
+		return merged.bind(names, opt, m)
+	}
 	if maparg, ok := convertMapStringInterface(arg); ok {
-		return bindMapArgs(names, maparg)
+		return bindMapArgs(names, opt, maparg, m)
+	}
+	if t := reflect.TypeOf(arg); t != nil && t.Kind() == reflect.Map {
+		return nil, fmt.Errorf("sqlx: unsupported map type: %T", arg)
 	}
-	return bindArgs(names, arg, m)
+	return bindArgs(names, opt, arg, m)
 }
 
 // private interface to generate a list of interfaces from a given struct
 // type, given a list of names to pull out of the struct.  Used by public
 // BindStruct interface.
-func bindArgs(names []string, arg any, m *reflectx.Mapper) ([]any, error) {
+func bindArgs(names []string, opt optionalNames, arg any, m *reflectx.Mapper) ([]any, error) {
 	arglist := make([]any, 0, len(names))
 
 	// grab the indirected value of arg
@@ -180,28 +210,56 @@ func bindArgs(names []string, arg any, m *reflectx.Mapper) ([]any, error) {
 		v = v.Elem()
 	}
 
+	tm := m.TypeMap(reflectx.Deref(v.Type()))
 	err := m.TraversalsByNameFunc(v.Type(), names, func(i int, t []int) error {
-		if len(t) == 0 {
-			return fmt.Errorf("could not find name %s in %#v", names[i], arg)
+		var val reflect.Value
+		var fi *reflectx.FieldInfo
+		if len(t) > 0 {
+			val, fi = reflectx.FieldByIndexesReadOnly(v, t), tm.GetByTraversal(t)
+		} else {
+			// the name may go through maps or pointers the mapper can't see
+			var ok bool
+			if val, fi, ok = resolveName(v, names[i], m); !ok {
+				dv, ok := opt.arg(names, i)
+				if !ok {
+					return fmt.Errorf("could not find name %s in %#v", names[i], arg)
+				}
+				arglist = append(arglist, dv)
+				return nil
+			}
 		}
-
-		val := reflectx.FieldByIndexesReadOnly(v, t)
-		arglist = append(arglist, val.Interface())
-
+		dv, err := bindValue(names[i], val, fi, m)
+		if err != nil {
+			return err
+		}
+		arglist = append(arglist, dv)
 		return nil
 	})
 
 	return arglist, err
 }
 
-// like bindArgs, but for maps.
-func bindMapArgs(names []string, arg map[string]any) ([]any, error) {
+// like bindArgs, but for maps.  Dotted names not found as keys are resolved
+// through the nested maps and structs in arg.
+func bindMapArgs(names []string, opt optionalNames, arg map[string]any, m *reflectx.Mapper) ([]any, error) {
 	arglist := make([]any, 0, len(names))
 
-	for _, name := range names {
+	for i, name := range names {
 		val, ok := arg[name]
+		if !ok && strings.Contains(name, ".") {
+			rv, fi, found := resolveName(reflect.ValueOf(arg), name, m)
+			if found {
+				dv, err := bindValue(name, rv, fi, m)
+				if err != nil {
+					return arglist, err
+				}
+				val, ok = dv, true
+			}
+		}
 		if !ok {
-			return arglist, fmt.Errorf("could not find name %s in %#v", name, arg)
+			if val, ok = opt.arg(names, i); !ok {
+				return arglist, fmt.Errorf("could not find name %s in %#v", name, arg)
+			}
 		}
 		arglist = append(arglist, val)
 	}
@@ -211,18 +269,21 @@ func bindMapArgs(names []string, arg map[string]any) ([]any, error) {
 // bindStruct binds a named parameter query with fields from a struct argument.
 // The rules for binding field names to parameter names follow the same
 // conventions as for StructScan, including obeying the `db` struct tags.
-func bindStruct(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
-	bound, names, err := compileNamedQuery([]byte(query), bindType)
+//
+// Slice fields making up a whole IN (...) list are expanded into one bindvar
+// per element.
+func bindStruct(bindType int, query string, arg any, m *reflectx.Mapper, c *NamedCache) (string, []any, error) {
+	q, err := c.parse(query, bindType)
 	if err != nil {
 		return "", []any{}, err
 	}
 
-	arglist, err := bindAnyArgs(names, arg, m)
+	arglist, err := bindAnyArgs(q.names, q.optional, arg, m)
 	if err != nil {
 		return "", []any{}, err
 	}
 
-	return bound, arglist, nil
+	return bindExpanded(q, bindType, arglist)
 }
 
 var valuesReg = regexp.MustCompile(`\)\s*(?i)VALUES\s*\(`)
@@ -271,45 +332,51 @@ func fixBound(bound string, loop int) string {
 
 // bindArray binds a named parameter query with fields from an array or slice of
 // structs argument.
-func bindArray(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
-	// do the initial binding with QUESTION;  if bindType is not question,
-	// we can rebind it at the end.
-	bound, names, err := compileNamedQuery([]byte(query), binder.QUESTION)
-	if err != nil {
-		return "", []any{}, err
+func bindArray(bindType int, query string, arg any, m *reflectx.Mapper, c *NamedCache) (string, []any, error) {
+	o, wrapped := arg.(OptionalArgs)
+	if wrapped {
+		arg = o.arg
 	}
 	arrayValue := reflect.ValueOf(arg)
 	arrayLen := arrayValue.Len()
 	if arrayLen == 0 {
 		return "", []any{}, fmt.Errorf("length of array is 0: %#v", arg)
 	}
-	var arglist = make([]any, 0, len(names)*arrayLen)
+	bound, q, err := c.compileArray(query, bindType, arrayLen)
+	if err != nil {
+		return "", []any{}, err
+	}
+	var arglist = make([]any, 0, len(q.names)*arrayLen)
 	for i := range arrayLen {
-		elemArglist, err := bindAnyArgs(names, arrayValue.Index(i).Interface(), m)
+		elem := arrayValue.Index(i).Interface()
+		if wrapped {
+			// each element is optional as the slice was
+			o.arg = elem
+			elem = o
+		}
+		elemArglist, err := bindAnyArgs(q.names, q.optional, elem, m)
 		if err != nil {
 			return "", []any{}, err
 		}
 		arglist = append(arglist, elemArglist...)
 	}
-	if arrayLen > 1 {
-		bound = fixBound(bound, arrayLen)
-	}
-	// adjust binding type if we weren't on question
-	if bindType != binder.QUESTION {
-		bound = binder.Default.Rebind(bindType, bound)
-	}
 	return bound, arglist, nil
 }
 
-// bindMap binds a named parameter query with a map of arguments.
-func bindMap(bindType int, query string, args map[string]any) (string, []any, error) {
-	bound, names, err := compileNamedQuery([]byte(query), bindType)
+// bindMap binds a named parameter query with a map of arguments.  Like with
+// bindStruct, slice values making up a whole IN (...) list are expanded into
+// one bindvar per element.
+func bindMap(bindType int, query string, args map[string]any, m *reflectx.Mapper, c *NamedCache) (string, []any, error) {
+	q, err := c.parse(query, bindType)
 	if err != nil {
 		return "", []any{}, err
 	}
 
-	arglist, err := bindMapArgs(names, args)
-	return bound, arglist, err
+	arglist, err := bindMapArgs(q.names, optionalNames{optional: q.optional}, args, m)
+	if err != nil {
+		return "", arglist, err
+	}
+	return bindExpanded(q, bindType, arglist)
 }
 
 // -- Compilation of Named Queries
@@ -320,112 +387,57 @@ func bindMap(bindType int, query string, args map[string]any) (string, []any, er
 // digits and numbers, where '5' is a digit but '五' is not.
 var allowedBindRunes = []*unicode.RangeTable{unicode.Letter, unicode.Digit}
 
-// compile a NamedQuery into an unbound query (using the '?' bindvar) and
-// a list of names.
+// compile a NamedQuery into an unbound query using the bindvars of bindType,
+// and a list of names.
 func compileNamedQuery(qs []byte, bindType int) (query string, names []string, err error) {
-	names = make([]string, 0, 10)
-	rebound := make([]byte, 0, len(qs))
-
-	inName := false
-	last := len(qs) - 1
-	currentVar := 1
-	name := make([]byte, 0, 10)
-
-	for i, b := range qs {
-		// a ':' while we're in a name is an error
-		if b == ':' {
-			// if this is the second ':' in a '::' escape sequence, append a ':'
-			if inName && i > 0 && qs[i-1] == ':' {
-				rebound = append(rebound, ':')
-				inName = false
-				continue
-			} else if inName {
-				err = errors.New("unexpected `:` while reading named param at " + strconv.Itoa(i))
-				return query, names, err
-			}
-			inName = true
-			name = []byte{}
-		} else if inName && i > 0 && b == '=' && len(name) == 0 {
-			rebound = append(rebound, ':', '=')
-			inName = false
-			continue
-			// if we're in a name, and this is an allowed character, continue
-		} else if inName && (unicode.IsOneOf(allowedBindRunes, rune(b)) || b == '_' || b == '.') && i != last {
-			// append the byte to the name if we are in a name and not on the last byte
-			name = append(name, b)
-			// if we're in a name and it's not an allowed character, the name is done
-		} else if inName {
-			inName = false
-			// if this is the final byte of the string and it is part of the name, then
-			// make sure to add it to the name
-			if i == last && unicode.IsOneOf(allowedBindRunes, rune(b)) {
-				name = append(name, b)
-			}
-			// add the string representation to the names list
-			names = append(names, string(name))
-			// add a proper bindvar for the bindType
-			switch bindType {
-			// oracle only supports named type bind vars even for positional
-			case binder.NAMED:
-				rebound = append(rebound, ':')
-				rebound = append(rebound, name...)
-			case binder.QUESTION, binder.UNKNOWN:
-				rebound = append(rebound, '?')
-			case binder.DOLLAR:
-				rebound = append(rebound, '$')
-				for _, b := range strconv.Itoa(currentVar) {
-					rebound = append(rebound, byte(b))
-				}
-				currentVar++
-			case binder.AT:
-				rebound = append(rebound, '@', 'p')
-				for _, b := range strconv.Itoa(currentVar) {
-					rebound = append(rebound, byte(b))
-				}
-				currentVar++
-			}
-			// add this byte to string unless it was not part of the name
-			if i != last {
-				rebound = append(rebound, b)
-			} else if !unicode.IsOneOf(allowedBindRunes, rune(b)) {
-				rebound = append(rebound, b)
-			}
-		} else {
-			// this is a normal byte and should just go onto the rebound query
-			rebound = append(rebound, b)
-		}
+	q, err := parseNamedQuery(string(qs), bindType)
+	if err != nil {
+		return "", []string{}, err
 	}
-
-	return string(rebound), names, err
+	return q.render(bindType, nil), q.names, nil
 }
 
 // BindNamed binds a struct or a map to a query with named parameters.
 // DEPRECATED: use sqlx.Named` instead of this, it may be removed in future.
 func BindNamed(bindType int, query string, arg any) (string, []any, error) {
-	return bindNamedMapper(bindType, query, arg, mapper())
+	return bindNamedMapper(bindType, query, arg, mapper(), defaultNamedCache)
 }
 
 // Named takes a query using named parameters and an argument and
 // returns a new query with a list of args that can be executed by
 // a database.  The return value uses the `?` bindvar.
 func Named(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(binder.QUESTION, query, arg, mapper())
+	return bindNamedMapper(binder.QUESTION, query, arg, mapper(), defaultNamedCache)
 }
 
-func bindNamedMapper(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
+// bindNamedMapper binds arg to query, taking the compiled form of query from c
+// if it was compiled before.
+func bindNamedMapper(bindType int, query string, arg any, m *reflectx.Mapper, c *NamedCache) (string, []any, error) {
 	t := reflect.TypeOf(arg)
+	if o, ok := arg.(OptionalArgs); ok {
+		t = reflect.TypeOf(o.source())
+	}
 	k := t.Kind()
 	switch {
 	case k == reflect.Map && t.Key().Kind() == reflect.String:
-		m, ok := convertMapStringInterface(arg)
+		o, wrapped := arg.(OptionalArgs)
+		if wrapped {
+			arg = o.source()
+		}
+		am, ok := convertMapStringInterface(arg)
 		if !ok {
 			return "", nil, fmt.Errorf("sqlx.bindNamedMapper: unsupported map type: %T", arg)
 		}
-		return bindMap(bindType, query, m)
+		if wrapped {
+			// bindStruct binds maps wrapped by Optional
+			o.arg = am
+			return bindStruct(bindType, query, o, m, c)
+		}
+		return bindMap(bindType, query, am, m, c)
 	case k == reflect.Array || k == reflect.Slice:
-		return bindArray(bindType, query, arg, m)
+		return bindArray(bindType, query, arg, m, c)
 	default:
-		return bindStruct(bindType, query, arg, m)
+		return bindStruct(bindType, query, arg, m, c)
 	}
 }
 
@@ -441,7 +453,7 @@ type Ext interface {
 // provided Ext (sqlx.Tx, sqlx.Db).  It works with both structs and with
 // map[string]any types.
 func NamedQuery(e Ext, query string, arg any) (*Rows, error) {
-	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, optionalFor(e, arg), mapperFor(e), namedCacheFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -452,7 +464,7 @@ func NamedQuery(e Ext, query string, arg any) (*Rows, error) {
 // then runs Exec on the result.  Returns an error from the binding
 // or the query execution itself.
 func NamedExec(e Ext, query string, arg any) (sql.Result, error) {
-	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, optionalFor(e, arg), mapperFor(e), namedCacheFor(e))
 	if err != nil {
 		return nil, err
 	}
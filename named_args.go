@@ -0,0 +1,77 @@
+package sqlx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// MergedArgs binds named parameters from several sources, see NamedArgs.
+type MergedArgs struct {
+	sources []any
+	strict  bool
+}
+
+// NamedArgs returns an argument for named queries which takes the value of
+// each name from several sources, each a struct or a map as accepted by
+// NamedExec, so that a request struct and a few extra values can be bound
+// together:
+//
+//	sqlx.NamedArgs(req, map[string]any{"tenant_id": tenant})
+//
+// Like successive assignments to a map, later sources take precedence over
+// earlier ones; use Strict to make a name found in more than one source an
+// error instead.  MergedArgs are accepted wherever a named query takes its
+// arg, including by NamedStmt.
+func NamedArgs(sources ...any) MergedArgs {
+	return MergedArgs{sources: sources}
+}
+
+// Strict returns a version of a which fails to bind a name found in more than
+// one of its sources, rather than taking it from the last one.
+func (a MergedArgs) Strict() MergedArgs {
+	a.strict = true
+	return a
+}
+
+// bind returns the values of names taken from the sources of a, the ones
+// marked optional in opt being allowed to be missing from all of them.
+func (a MergedArgs) bind(names []string, opt optionalNames, m *reflectx.Mapper) ([]any, error) {
+	arglist := make([]any, 0, len(names))
+	for n, name := range names {
+		found := -1
+		var val reflect.Value
+		var fi *reflectx.FieldInfo
+		for i := len(a.sources) - 1; i >= 0; i-- {
+			v, f, ok := resolveName(reflect.ValueOf(a.sources[i]), name, m)
+			if !ok {
+				continue
+			}
+			if found >= 0 {
+				if a.strict {
+					return arglist, fmt.Errorf("name %s found in both source %d and source %d", name, i, found)
+				}
+				continue
+			}
+			found, val, fi = i, v, f
+			if !a.strict {
+				break
+			}
+		}
+		if found < 0 {
+			dv, ok := opt.arg(names, n)
+			if !ok {
+				return arglist, fmt.Errorf("could not find name %s in any of %d sources", name, len(a.sources))
+			}
+			arglist = append(arglist, dv)
+			continue
+		}
+		dv, err := bindValue(name, val, fi, m)
+		if err != nil {
+			return arglist, err
+		}
+		arglist = append(arglist, dv)
+	}
+	return arglist, nil
+}
@@ -0,0 +1,175 @@
+package sqlx
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+)
+
+// DefaultNamedCacheSize is the number of compiled named queries a DB keeps in
+// its NamedCache unless told otherwise with SetNamedCacheSize.
+const DefaultNamedCacheSize = 500
+
+// defaultNamedCache is used where there is no DB to take a cache from, as in
+// the package level BindNamed and Named.
+var defaultNamedCache = NewNamedCache(DefaultNamedCacheSize)
+
+// NamedCache is a least recently used cache of compiled named queries, keyed
+// by the query and the bindtype it was compiled for.  It is safe for
+// concurrent use.  A nil *NamedCache caches nothing.
+type NamedCache struct {
+	mu           sync.Mutex
+	size         int
+	entries      map[namedCacheKey]*list.Element
+	order        *list.List
+	hits, misses uint64
+}
+
+// NamedCacheStats reports how well a NamedCache is doing.
+type NamedCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Len is the number of queries cached, and Size the most it will hold.
+	Len  int
+	Size int
+}
+
+type namedCacheKey struct {
+	query    string
+	bindType int
+}
+
+type namedCacheEntry struct {
+	key   namedCacheKey
+	query *namedQuery
+}
+
+// NewNamedCache returns a NamedCache holding up to size compiled queries.  A
+// size of 0 or less disables caching.
+func NewNamedCache(size int) *NamedCache {
+	return &NamedCache{size: size, entries: make(map[namedCacheKey]*list.Element), order: list.New()}
+}
+
+// Stats returns the hit and miss counts of the cache and its current size.
+func (c *NamedCache) Stats() NamedCacheStats {
+	if c == nil {
+		return NamedCacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return NamedCacheStats{Hits: c.hits, Misses: c.misses, Len: c.order.Len(), Size: c.size}
+}
+
+// Resize changes the number of queries the cache holds, evicting the least
+// recently used ones if needed.  A size of 0 or less disables caching.
+func (c *NamedCache) Resize(size int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = size
+	c.evict()
+}
+
+func (c *NamedCache) get(key namedCacheKey) (*namedCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return nil, false
+	}
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*namedCacheEntry), true
+}
+
+func (c *NamedCache) add(e *namedCacheEntry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	if el, ok := c.entries[e.key]; ok {
+		// another goroutine compiled it first
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	c.evict()
+}
+
+// evict drops the least recently used entries until the cache fits its size.
+// c.mu must be held.
+func (c *NamedCache) evict() {
+	for c.order.Len() > max(c.size, 0) {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*namedCacheEntry).key)
+	}
+}
+
+// parse returns the parsed form of query for bindType.
+func (c *NamedCache) parse(query string, bindType int) (*namedQuery, error) {
+	key := namedCacheKey{query: query, bindType: bindType}
+	if e, ok := c.get(key); ok {
+		return e.query, nil
+	}
+	q, err := parseNamedQuery(query, bindType)
+	if err != nil {
+		return nil, err
+	}
+	c.add(&namedCacheEntry{key: key, query: q})
+	return q, nil
+}
+
+// compileArray returns query compiled for bindType with its VALUES list
+// repeated n times, as bindArray binds it, and the parsed query holding the
+// parameters of one list.  Only the parsed query is cached, as the expanded
+// one grows with n, which changes with each batch.
+func (c *NamedCache) compileArray(query string, bindType, n int) (string, *namedQuery, error) {
+	// do the initial binding with QUESTION;  if bindType is not question,
+	// we can rebind it at the end.
+	q, err := c.parse(query, binder.QUESTION)
+	if err != nil {
+		return "", nil, err
+	}
+	bound := q.render(binder.QUESTION, nil)
+	if n > 1 {
+		bound = fixBound(bound, n)
+	}
+	// adjust binding type if we weren't on question
+	if bindType != binder.QUESTION {
+		bound = binder.Default.Rebind(bindType, bound)
+	}
+	return bound, q, nil
+}
+
+// namedCacheFor returns the NamedCache of i if it is an sqlx.DB, Tx or Conn
+// with one, or the default cache.
+func namedCacheFor(i any) *NamedCache {
+	var c *NamedCache
+	switch i := i.(type) {
+	case *DB:
+		c = i.namedCache
+	case *Tx:
+		c = i.namedCache
+	case *Conn:
+		c = i.namedCache
+	}
+	if c == nil {
+		return defaultNamedCache
+	}
+	return c
+}
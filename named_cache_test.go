@@ -0,0 +1,132 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+)
+
+func TestNamedCache(t *testing.T) {
+	c := NewNamedCache(2)
+	for _, q := range []string{"SELECT :a", "SELECT :b", "SELECT :a", "SELECT :c", "SELECT :a"} {
+		if _, err := c.parse(q, binder.DOLLAR); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// :b was evicted when :c came in, as :a had been used since
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Len != 2 || stats.Size != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if _, ok := c.get(namedCacheKey{query: "SELECT :b", bindType: binder.DOLLAR}); ok {
+		t.Error("expected SELECT :b to be evicted")
+	}
+
+	// the same query is cached separately for each bindtype
+	if _, err := c.parse("SELECT :a", binder.QUESTION); err != nil {
+		t.Fatal(err)
+	}
+	if stats := c.Stats(); stats.Misses != 5 {
+		t.Errorf("expected a miss for another bindtype, got %+v", stats)
+	}
+
+	bound, q, err := c.compileArray("INSERT INTO t (a) VALUES (:a)", binder.DOLLAR, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bound != "INSERT INTO t (a) VALUES ($1),($2),($3)" || len(q.names) != 1 {
+		t.Errorf("unexpected compilation: %s %v", bound, q.names)
+	}
+	// batches of any size share the parsed query
+	before := c.Stats().Len
+	for n := 1; n <= 5; n++ {
+		if bound, _, err = c.compileArray("INSERT INTO t (a) VALUES (:a)", binder.DOLLAR, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bound != "INSERT INTO t (a) VALUES ($1),($2),($3),($4),($5)" {
+		t.Errorf("unexpected compilation: %s", bound)
+	}
+	if stats := c.Stats(); stats.Len != before {
+		t.Errorf("expected no entry per batch size, got %+v", stats)
+	}
+
+	c.Resize(0)
+	if stats := c.Stats(); stats.Len != 0 {
+		t.Errorf("expected an empty cache, got %+v", stats)
+	}
+	if _, err := c.parse("SELECT :a", binder.DOLLAR); err != nil {
+		t.Fatal(err)
+	}
+	if stats := c.Stats(); stats.Len != 0 {
+		t.Errorf("expected a disabled cache to stay empty, got %+v", stats)
+	}
+
+	// a nil cache caches nothing
+	var none *NamedCache
+	none.Resize(10)
+	if _, err := none.parse("SELECT :a", binder.DOLLAR); err != nil {
+		t.Fatal(err)
+	}
+	if stats := none.Stats(); stats != (NamedCacheStats{}) {
+		t.Errorf("expected zero stats for a nil cache, got %+v", stats)
+	}
+	if stats := (&DB{}).NamedCacheStats(); stats != (NamedCacheStats{}) {
+		t.Errorf("expected zero stats for a DB without a cache, got %+v", stats)
+	}
+
+	c = NewNamedCache(10)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				q := fmt.Sprintf("SELECT :a%d", (i+j)%20)
+				if _, _, err := bindStruct(binder.QUESTION, q, map[string]any{}, mapper(), c); err == nil {
+					t.Error("expected an error for a missing name")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if stats := c.Stats(); stats.Hits+stats.Misses != 800 || stats.Len != 10 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestDBNamedCache(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		db.SetNamedCacheSize(10)
+		before := db.NamedCacheStats()
+		for range 3 {
+			_, err := db.NamedExecContext(ctx, "UPDATE person SET email = :email WHERE first_name = :first", map[string]any{"email": "a@b.c", "first": "John"})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		// transactions share the cache of their DB
+		tx := db.MustBeginTx(ctx, nil)
+		defer tx.Rollback()
+		if _, err := tx.NamedExecContext(ctx, "UPDATE person SET email = :email WHERE first_name = :first", map[string]any{"email": "d@e.f", "first": "John"}); err != nil {
+			t.Fatal(err)
+		}
+		after := db.NamedCacheStats()
+		if after.Misses-before.Misses != 1 || after.Hits-before.Hits != 3 {
+			t.Errorf("expected 1 miss and 3 hits, got %+v then %+v", before, after)
+		}
+
+		db.SetNamedCacheSize(0)
+		if _, _, err := db.BindNamed("SELECT :x", map[string]any{"x": 1}); err != nil {
+			t.Fatal(err)
+		}
+		if stats := db.NamedCacheStats(); stats.Len != 0 || stats.Size != 0 {
+			t.Errorf("expected a disabled cache, got %+v", stats)
+		}
+	})
+}
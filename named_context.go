@@ -16,39 +16,47 @@ type namedPreparerContext interface {
 
 func prepareNamedContext(ctx context.Context, p namedPreparerContext, query string) (*NamedStmt, error) {
 	bindType := binder.Default.Type(p.DriverName())
-	q, args, err := compileNamedQuery([]byte(query), bindType)
+	parsed, err := parseNamedQuery(query, bindType)
 	if err != nil {
 		return nil, err
 	}
+	q := parsed.render(bindType, nil)
 	stmt, err := PreparexContext(ctx, p, q)
 	if err != nil {
 		return nil, err
 	}
 	return &NamedStmt{
 		QueryString: q,
-		Params:      args,
+		Params:      parsed.names,
 		Stmt:        stmt,
+		shapes: &namedShapes{query: parsed, bindType: bindType, prepare: func(ctx context.Context, q string) (*Stmt, error) {
+			return PreparexContext(ctx, p, q)
+		}},
+		optional: isOptionalNamed(p),
+		defaults: namedDefaultsFor(p),
 	}, nil
 }
 
 // ExecContext executes a named statement using the struct passed.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) ExecContext(ctx context.Context, arg any) (sql.Result, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(ctx, arg)
 	if err != nil {
 		return *new(sql.Result), err
 	}
-	return n.Stmt.ExecContext(ctx, args...)
+	defer release()
+	return stmt.ExecContext(ctx, args...)
 }
 
 // QueryContext executes a named statement using the struct argument, returning rows.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) QueryContext(ctx context.Context, arg any) (*sql.Rows, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(ctx, arg)
 	if err != nil {
 		return nil, err
 	}
-	return n.Stmt.QueryContext(ctx, args...)
+	defer release()
+	return stmt.QueryContext(ctx, args...)
 }
 
 // QueryRowContext executes a named statement against the database.  Because sqlx cannot
@@ -56,11 +64,12 @@ func (n *NamedStmt) QueryContext(ctx context.Context, arg any) (*sql.Rows, error
 // returns a *sqlx.Row instead.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) QueryRowContext(ctx context.Context, arg any) *Row {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
+	stmt, args, release, err := n.bind(ctx, arg)
 	if err != nil {
 		return &Row{err: err}
 	}
-	return n.Stmt.QueryRowxContext(ctx, args...)
+	defer release()
+	return stmt.QueryRowxContext(ctx, args...)
 }
 
 // MustExecContext execs a NamedStmt, panicing on error
@@ -80,7 +89,7 @@ func (n *NamedStmt) QueryxContext(ctx context.Context, arg any) (*Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, Mapper: n.Stmt.Mapper, unsafe: isUnsafe(n)}, err
+	return &Rows{Rows: r, Mapper: n.Stmt.Mapper, unsafe: isUnsafe(n), strict: isStrict(n), nullZero: isNullTolerant(n)}, err
 }
 
 // QueryRowxContext this NamedStmt.  Because of limitations with QueryRow, this is
@@ -113,7 +122,7 @@ func (n *NamedStmt) GetContext(ctx context.Context, dest any, arg any) error {
 // provided Ext (sqlx.Tx, sqlx.Db).  It works with both structs and with
 // map[string]any types.
 func NamedQueryContext(ctx context.Context, e ExtContext, query string, arg any) (*Rows, error) {
-	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, optionalFor(e, arg), mapperFor(e), namedCacheFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +133,7 @@ func NamedQueryContext(ctx context.Context, e ExtContext, query string, arg any)
 // then runs Exec on the result.  Returns an error from the binding
 // or the query execution itself.
 func NamedExecContext(ctx context.Context, e ExtContext, query string, arg any) (sql.Result, error) {
-	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(binder.Default.Type(e.DriverName()), query, optionalFor(e, arg), mapperFor(e), namedCacheFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,242 @@
+package sqlx
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// expandArgs flattens the slice values in args, which were bound to the names
+// of q, so that each element is passed as its own argument.  Only parameters
+// making up a whole IN (...) list are expanded; other slices are passed as
+// they are, eg. for = ANY(:ids), as are values implementing driver.Valuer and
+// []byte.  It returns the number of bindvars each name needs, or nil counts if
+// every name needs just one.
+func expandArgs(q *namedQuery, args []any) ([]int, []any, error) {
+	var counts []int
+	n, expanded := len(args), false
+	for i, arg := range args {
+		if !q.expand[i] {
+			continue
+		}
+		v, ok := expandable(arg)
+		if !ok {
+			continue
+		}
+		if v.Len() == 0 {
+			return nil, nil, fmt.Errorf("empty slice passed for named parameter %s", q.names[i])
+		}
+		if counts == nil {
+			counts = make([]int, len(args))
+			for j := range counts {
+				counts[j] = 1
+			}
+		}
+		counts[i] = v.Len()
+		n += v.Len() - 1
+		expanded = expanded || v.Len() > 1
+	}
+	if counts == nil {
+		return nil, args, nil
+	}
+
+	flat := make([]any, 0, n)
+	for i, arg := range args {
+		v, ok := expandable(arg)
+		if !ok || !q.expand[i] {
+			flat = append(flat, arg)
+			continue
+		}
+		for j := range v.Len() {
+			flat = append(flat, v.Index(j).Interface())
+		}
+	}
+	if !expanded {
+		// only single element slices, which bind like plain values
+		counts = nil
+	}
+	return counts, flat, nil
+}
+
+// expandable returns the slice held by arg, if it should be expanded into
+// one bindvar per element.
+func expandable(arg any) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Value{}, false
+	}
+	t := reflect.TypeOf(arg)
+	if t.Implements(_valuerInterface) {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() || v.Type().Implements(_valuerInterface) {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// bindExpanded binds args to the names of q and renders it for bindType,
+// expanding slice arguments into as many bindvars as they have elements.
+func bindExpanded(q *namedQuery, bindType int, args []any) (string, []any, error) {
+	counts, args, err := expandArgs(q, args)
+	if err != nil {
+		return "", []any{}, err
+	}
+	if isNativeNamed(bindType) {
+		args = nativeArgs(q.argNames(counts), args)
+	}
+	return q.render(bindType, counts), args, nil
+}
+
+// maxNamedShapes is the number of statements a NamedStmt keeps prepared for
+// slice arguments.  Beyond it, the least recently used one is closed, so that
+// lists of ever changing lengths don't exhaust the server's prepared
+// statements.
+const maxNamedShapes = 16
+
+// namedShapes prepares and caches the statements a NamedStmt needs for
+// arguments holding slices, one for each combination of slice lengths.
+type namedShapes struct {
+	query    *namedQuery
+	bindType int
+	prepare  func(ctx context.Context, query string) (*Stmt, error)
+
+	mu    sync.Mutex
+	stmts map[string]*list.Element
+	order *list.List
+}
+
+type namedShape struct {
+	key  string
+	stmt *Stmt
+	// uses counts the callers running the statement; once evicted, the
+	// statement is closed when the last of them releases it.
+	uses    int
+	evicted bool
+}
+
+// stmt returns the shape for counts, preparing its statement if needed.  The
+// shape must be given back to release once the statement has been run.
+func (s *namedShapes) stmt(ctx context.Context, counts []int) (*namedShape, error) {
+	var key strings.Builder
+	for _, c := range counts {
+		key.WriteString(strconv.Itoa(c))
+		key.WriteByte(',')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.stmts[key.String()]; ok {
+		s.order.MoveToFront(el)
+		shape := el.Value.(*namedShape)
+		shape.uses++
+		return shape, nil
+	}
+	stmt, err := s.prepare(ctx, s.query.render(s.bindType, counts))
+	if err != nil {
+		return nil, err
+	}
+	if s.stmts == nil {
+		s.stmts = make(map[string]*list.Element)
+		s.order = list.New()
+	}
+	shape := &namedShape{key: key.String(), stmt: stmt, uses: 1}
+	s.stmts[shape.key] = s.order.PushFront(shape)
+	for s.order.Len() > maxNamedShapes {
+		el := s.order.Back()
+		s.order.Remove(el)
+		evicted := el.Value.(*namedShape)
+		delete(s.stmts, evicted.key)
+		evicted.evicted = true
+		if evicted.uses == 0 {
+			evicted.stmt.Close()
+		}
+	}
+	return shape, nil
+}
+
+// release gives back a shape returned by stmt, closing its statement if it
+// was evicted meanwhile and nobody else is running it.
+func (s *namedShapes) release(shape *namedShape) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shape.uses--
+	if shape.evicted && shape.uses == 0 {
+		shape.stmt.Close()
+	}
+}
+
+// on returns a namedShapes for the same query which prepares its statements
+// within tx.  The statements are closed when tx is committed or rolled back.
+func (s *namedShapes) on(tx *Tx) *namedShapes {
+	if s == nil {
+		return nil
+	}
+	return &namedShapes{query: s.query, bindType: s.bindType, prepare: func(ctx context.Context, q string) (*Stmt, error) {
+		return PreparexContext(ctx, tx, q)
+	}}
+}
+
+// close closes all of the statements prepared so far.
+func (s *namedShapes) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for key, el := range s.stmts {
+		errs = append(errs, el.Value.(*namedShape).stmt.Close())
+		delete(s.stmts, key)
+	}
+	if s.order != nil {
+		s.order.Init()
+	}
+	return errors.Join(errs...)
+}
+
+// bind binds arg to the parameters of the statement, returning the statement
+// to execute with the resulting args, and a func to call once it has been
+// run.  When arg holds slices for IN lists, the statement is one prepared for
+// their lengths.
+func (n *NamedStmt) bind(ctx context.Context, arg any) (*Stmt, []any, func(), error) {
+	var optional []bool
+	if n.shapes != nil {
+		optional = n.shapes.query.optional
+	}
+	arg = optionalFor(n, arg)
+	args, err := bindAnyArgs(n.Params, optional, arg, n.Stmt.Mapper)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if n.shapes == nil {
+		// without the parsed query, slices are passed as they are
+		return n.Stmt, args, func() {}, nil
+	}
+	counts, args, err := expandArgs(n.shapes.query, args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if isNativeNamed(n.shapes.bindType) {
+		args = nativeArgs(n.shapes.query.argNames(counts), args)
+	}
+	if counts == nil {
+		return n.Stmt, args, func() {}, nil
+	}
+	shape, err := n.shapes.stmt(ctx, counts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// carry over the options set on this statement, eg. by Unsafe
+	stmt := *n.Stmt
+	stmt.Stmt = shape.stmt.Stmt
+	return &stmt, args, func() { n.shapes.release(shape) }, nil
+}
@@ -0,0 +1,367 @@
+package sqlx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+)
+
+// A namedQuery is a named query split into the names of its parameters and
+// the text around them, so that it can be rendered for any bindtype.  text
+// always holds one more element than names.  optional tells which parameters
+// were written as :name?, which bind NULL rather than fail when missing, and
+// expand which ones make up a whole IN (...) list, whose slice arguments are
+// expanded into one bindvar per element.
+type namedQuery struct {
+	text     []string
+	names    []string
+	optional []bool
+	expand   []bool
+}
+
+// parseNamedQuery splits a named query into its parameters and the text around
+// them.  Parameters are a ':' followed by a name made of letters, digits, '_'
+// and '.', and optionally a '?' marking the parameter optional.  Comments are
+// copied verbatim, as are '::' casts and ':=' assignments.  No parameters are
+// read in string literals, quoted identifiers and dollar-quoted strings.
+//
+// For compatibility with queries written for the byte-wise compiler this lexer
+// replaced, colons can still be escaped by doubling them.  Within string
+// literals, quoted identifiers and dollar-quoted strings alike, each '::'
+// stands for a single ':', so a literal '::' must be written '::::'.  Elsewhere
+// a lone '::' is a cast, but longer runs are escapes, so that '::::' gives '::'
+// and ':::name' a ':' followed by the parameter name.
+//
+// Quotes are escaped by doubling them.  Backslash escapes are also understood
+// in quoted strings, except for the DOLLAR bindtype, where Postgres only allows
+// them in E'...' strings.
+func parseNamedQuery(qs string, bindType int) (*namedQuery, error) {
+	l := &namedLexer{src: qs, line: 1, col: 1, backslash: bindType != binder.DOLLAR}
+	if err := l.run(); err != nil {
+		return nil, err
+	}
+	return &namedQuery{text: append(l.text, l.textUntil(len(l.src))), names: l.names, optional: l.optional, expand: l.expand}, nil
+}
+
+// render returns the query with each parameter replaced by a bindvar of
+// bindType, named as by argNames for the ATNAME and COLONNAME bindtypes.  If
+// counts is not nil, the ith parameter is replaced by counts[i] comma
+// separated bindvars, as needed to bind the elements of a slice.
+func (q *namedQuery) render(bindType int, counts []int) string {
+	var b strings.Builder
+	size := 0
+	for _, t := range q.text {
+		size += len(t)
+	}
+	b.Grow(size + 4*len(q.names))
+
+	var argNames []string
+	if isNativeNamed(bindType) {
+		argNames = q.argNames(counts)
+	}
+
+	n := 1
+	for i, name := range q.names {
+		b.WriteString(q.text[i])
+		count := 1
+		if counts != nil {
+			count = counts[i]
+		}
+		for j := range count {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			switch bindType {
+			// oracle only supports named type bind vars even for positional
+			case binder.NAMED:
+				b.WriteByte(':')
+				b.WriteString(name)
+				if count > 1 {
+					b.WriteByte('_')
+					b.WriteString(strconv.Itoa(j + 1))
+				}
+			case binder.QUESTION, binder.UNKNOWN:
+				b.WriteByte('?')
+			case binder.DOLLAR:
+				b.WriteByte('$')
+				b.WriteString(strconv.Itoa(n))
+			case binder.AT:
+				b.WriteString("@p")
+				b.WriteString(strconv.Itoa(n))
+			case binder.ATNAME:
+				b.WriteByte('@')
+				b.WriteString(argNames[n-1])
+			case binder.COLONNAME:
+				b.WriteByte(':')
+				b.WriteString(argNames[n-1])
+			}
+			n++
+		}
+	}
+	b.WriteString(q.text[len(q.text)-1])
+	return b.String()
+}
+
+// A namedLexer walks a named query rune by rune, keeping track of the line
+// and column for error messages.
+type namedLexer struct {
+	src       string
+	pos       int
+	line, col int
+	backslash bool
+	// start is the position where the text after the last parameter begins,
+	// or resumes after escaped colons were dropped; buf holds the text before
+	// the colons.
+	start    int
+	buf      strings.Builder
+	text     []string
+	names    []string
+	optional []bool
+	expand   []bool
+}
+
+// next returns the rune at the current position and advances past it, or -1
+// at the end of the query.
+func (l *namedLexer) next() rune {
+	if l.pos >= len(l.src) {
+		return -1
+	}
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+// peek returns the rune at the current position without advancing.
+func (l *namedLexer) peek() rune {
+	if l.pos >= len(l.src) {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return r
+}
+
+// colons advances past the colons at the current position, returning how many
+// there were.
+func (l *namedLexer) colons() int {
+	n := 0
+	for l.peek() == ':' {
+		l.next()
+		n++
+	}
+	return n
+}
+
+// drop removes src[from:to] from the text of the query.
+func (l *namedLexer) drop(from, to int) {
+	l.buf.WriteString(l.src[l.start:from])
+	l.start = to
+}
+
+// textUntil returns the text from the end of the last parameter up to end.
+func (l *namedLexer) textUntil(end int) string {
+	text := l.buf.String() + l.src[l.start:end]
+	l.buf.Reset()
+	return text
+}
+
+// errorf returns an error located at the given line and column.
+func (l *namedLexer) errorf(line, col int, format string, args ...any) error {
+	return fmt.Errorf("%s at line %d, column %d", fmt.Sprintf(format, args...), line, col)
+}
+
+func (l *namedLexer) run() error {
+	for {
+		line, col := l.line, l.col
+		r := l.next()
+		switch {
+		case r == -1:
+			return nil
+		case r == '\'':
+			escapes := l.backslash || l.afterEscapePrefix()
+			if err := l.quoted('\'', escapes, "string literal", line, col); err != nil {
+				return err
+			}
+		case r == '"':
+			if err := l.quoted('"', l.backslash, "quoted identifier", line, col); err != nil {
+				return err
+			}
+		case r == '`':
+			if err := l.quoted('`', false, "quoted identifier", line, col); err != nil {
+				return err
+			}
+		case r == '-' && l.peek() == '-':
+			for r != '\n' && r != -1 {
+				r = l.next()
+			}
+		case r == '/' && l.peek() == '*':
+			if err := l.blockComment(line, col); err != nil {
+				return err
+			}
+		case r == '$':
+			if err := l.dollarQuoted(line, col); err != nil {
+				return err
+			}
+		case r == ':':
+			l.colon()
+		}
+	}
+}
+
+// afterEscapePrefix reports whether the quote just read opens an E'...'
+// string, in which Postgres allows backslash escapes.
+func (l *namedLexer) afterEscapePrefix() bool {
+	i := l.pos - 1
+	if i < 1 || (l.src[i-1] != 'E' && l.src[i-1] != 'e') {
+		return false
+	}
+	if i < 2 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(l.src[:i-1])
+	return !isNameRune(prev)
+}
+
+// quoted skips to the end of a string opened by quote.  Doubled quotes, and
+// backslash escapes if escapes is set, do not end it.
+func (l *namedLexer) quoted(quote rune, escapes bool, what string, line, col int) error {
+	for {
+		switch r := l.next(); {
+		case r == -1:
+			return l.errorf(line, col, "unterminated %s", what)
+		case r == '\\' && escapes:
+			l.next()
+		case r == quote:
+			if l.peek() != quote {
+				return nil
+			}
+			l.next()
+		case r == ':':
+			l.escapedColon()
+		}
+	}
+}
+
+// escapedColon drops the second colon of a '::' escape within quotes, if the
+// ':' just read starts one.
+func (l *namedLexer) escapedColon() {
+	if l.peek() == ':' {
+		l.next()
+		l.drop(l.pos-1, l.pos)
+	}
+}
+
+// blockComment skips a /* */ comment, which may be nested as in Postgres.
+func (l *namedLexer) blockComment(line, col int) error {
+	l.next()
+	depth := 1
+	for depth > 0 {
+		switch r := l.next(); {
+		case r == -1:
+			return l.errorf(line, col, "unterminated comment")
+		case r == '/' && l.peek() == '*':
+			l.next()
+			depth++
+		case r == '*' && l.peek() == '/':
+			l.next()
+			depth--
+		}
+	}
+	return nil
+}
+
+// dollarQuoted skips a Postgres dollar-quoted string such as $$...$$ or
+// $body$...$body$.  A '$' which does not open one, as in the $1 bindvar, is
+// left alone.
+func (l *namedLexer) dollarQuoted(line, col int) error {
+	rest := l.src[l.pos:]
+	end := strings.IndexByte(rest, '$')
+	if end < 0 {
+		return nil
+	}
+	tag := rest[:end]
+	for i, r := range tag {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return nil
+		}
+	}
+
+	delim := "$" + tag + "$"
+	body := strings.Index(rest[end+1:], delim)
+	if body < 0 {
+		return l.errorf(line, col, "unterminated dollar-quoted string")
+	}
+	stop := l.pos + end + 1 + body + len(delim)
+	for l.pos < stop {
+		if l.next() == ':' {
+			l.escapedColon()
+		}
+	}
+	return nil
+}
+
+// colon handles a ':' just read, which starts a parameter unless it is part
+// of a '::' cast, a ':=' assignment or a run of escaped colons.
+func (l *namedLexer) colon() {
+	switch n := 1 + l.colons(); {
+	case n == 2:
+		// a cast, whose type name must not be read as a name
+		return
+	case n%2 == 0:
+		l.drop(l.pos-n/2, l.pos)
+		return
+	case n > 2:
+		// the last colon may still start a parameter
+		l.drop(l.pos-1-n/2, l.pos-1)
+	}
+	if r := l.peek(); r == '=' || !isNameRune(r) {
+		return
+	}
+
+	begin := l.pos - 1
+	for isNameRune(l.peek()) {
+		l.next()
+	}
+	l.text = append(l.text, l.textUntil(begin))
+	l.names = append(l.names, l.src[begin+1:l.pos])
+	optional := l.peek() == '?'
+	if optional {
+		l.next()
+	}
+	l.optional = append(l.optional, optional)
+	l.expand = append(l.expand, l.inList(begin))
+	l.start = l.pos
+}
+
+// inList reports whether the parameter which starts at begin and was just read
+// is the whole of an IN (...) list.
+func (l *namedLexer) inList(begin int) bool {
+	after := strings.TrimLeftFunc(l.src[l.pos:], unicode.IsSpace)
+	if !strings.HasPrefix(after, ")") {
+		return false
+	}
+	before, ok := strings.CutSuffix(strings.TrimRightFunc(l.src[:begin], unicode.IsSpace), "(")
+	if !ok {
+		return false
+	}
+	before = strings.TrimRightFunc(before, unicode.IsSpace)
+	if len(before) < 2 || !strings.EqualFold(before[len(before)-2:], "in") {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(before[:len(before)-2])
+	return !isNameRune(prev)
+}
+
+// isNameRune reports whether r can be part of a parameter name.
+func isNameRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsOneOf(allowedBindRunes, r)
+}
@@ -0,0 +1,83 @@
+package sqlx
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+)
+
+// isNativeNamed reports whether bindType passes named queries to the driver
+// with its own named parameters rather than positional ones.
+func isNativeNamed(bindType int) bool {
+	return bindType == binder.ATNAME || bindType == binder.COLONNAME
+}
+
+// argNames returns the name of the sql.NamedArg for each bindvar of q, with
+// the ith parameter expanded to counts[i] bindvars if counts is not nil.  A
+// parameter used more than once gets the same name each time.  Names are made
+// valid for database/sql, which only allows letters, digits and '_', starting
+// with a letter.
+func (q *namedQuery) argNames(counts []int) []string {
+	var names []string
+	// taken maps the names given so far to the bindvars they stand for, and
+	// given the other way around.
+	taken := make(map[string]string)
+	given := make(map[string]string)
+	for i, name := range q.names {
+		count := 1
+		if counts != nil {
+			count = counts[i]
+		}
+		for j := range count {
+			key, want := name, argName(name)
+			if count > 1 {
+				suffix := "_" + strconv.Itoa(j+1)
+				key, want = name+"\x00"+suffix, want+suffix
+			}
+			if n, ok := given[key]; ok {
+				names = append(names, n)
+				continue
+			}
+			n := want
+			for k := 2; taken[n] != ""; k++ {
+				n = want + "_" + strconv.Itoa(k)
+			}
+			taken[n], given[key] = key, n
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// argName returns name made into a valid sql.NamedArg name.
+func argName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(r) {
+		name = "p" + name
+	}
+	return name
+}
+
+// nativeArgs returns args, bound to the bindvars named by names, as
+// sql.NamedArgs, sending the value of a name used more than once only once.
+func nativeArgs(names []string, args []any) []any {
+	named := make([]any, 0, len(args))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		named = append(named, sql.Named(name, args[i]))
+	}
+	return named
+}
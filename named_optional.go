@@ -0,0 +1,138 @@
+package sqlx
+
+import "maps"
+
+// OptionalArgs binds named parameters missing from the arg it wraps as NULL,
+// or as a default given with WithDefaults, see Optional.
+type OptionalArgs struct {
+	arg any
+	// all makes every parameter optional, not just those written :name?.
+	all      bool
+	defaults map[string]any
+}
+
+// Optional wraps arg so that every named parameter of the query it is bound
+// to is optional, as if written :name?.  A parameter missing from arg, be it
+// a map key or a struct path, binds NULL, or its default if one was given with
+// WithDefaults or NamedDefaults.  Optional can wrap anything accepted as a
+// named arg, including a slice and MergedArgs.
+func Optional(arg any) OptionalArgs {
+	return OptionalArgs{arg: arg, all: true}
+}
+
+// WithDefaults returns a version of a which binds optional parameters missing
+// from the arg to their value in defaults rather than NULL.  They take
+// precedence over the defaults of the DB, Tx or Conn running the query.
+func (a OptionalArgs) WithDefaults(defaults map[string]any) OptionalArgs {
+	a.defaults = mergeDefaults(a.defaults, defaults)
+	return a
+}
+
+// source returns the arg wrapped by a, or an empty map if it is nil, so that
+// every parameter binds its default or NULL.
+func (a OptionalArgs) source() any {
+	if a.arg == nil {
+		return map[string]any{}
+	}
+	return a.arg
+}
+
+// mergeDefaults returns the defaults in base overridden by those in over,
+// leaving both unchanged.
+func mergeDefaults(base, over map[string]any) map[string]any {
+	if len(over) == 0 {
+		return base
+	}
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]any, len(over))
+	}
+	maps.Copy(merged, over)
+	return merged
+}
+
+// optionalNames tells which of the names bound by a query may be missing from
+// the arg, and the values they bind then instead of NULL.
+type optionalNames struct {
+	optional []bool
+	defaults map[string]any
+}
+
+// arg returns the value to bind for the ith of names, which is missing from
+// the arg, if it is optional.
+func (o optionalNames) arg(names []string, i int) (any, bool) {
+	if i >= len(o.optional) || !o.optional[i] {
+		return nil, false
+	}
+	return o.defaults[names[i]], true
+}
+
+// unwrapOptional returns the arg wrapped by arg if it is OptionalArgs, and the
+// optional names of a query whose :name? parameters are marked in optional.
+func unwrapOptional(arg any, names []string, optional []bool) (any, optionalNames) {
+	o, ok := arg.(OptionalArgs)
+	if !ok {
+		return arg, optionalNames{optional: optional}
+	}
+	if o.all {
+		optional = allOptional(len(names))
+	}
+	return o.source(), optionalNames{optional: optional, defaults: o.defaults}
+}
+
+// allOptional returns an optional list marking all of n names optional.
+func allOptional(n int) []bool {
+	optional := make([]bool, n)
+	for i := range optional {
+		optional[i] = true
+	}
+	return optional
+}
+
+// isOptionalNamed reports whether i is an sqlx.DB, Tx, Conn or NamedStmt which
+// makes all named parameters optional.
+func isOptionalNamed(i any) bool {
+	switch i := i.(type) {
+	case *DB:
+		return i.optionalNamed
+	case *Tx:
+		return i.optionalNamed
+	case *Conn:
+		return i.optionalNamed
+	case *NamedStmt:
+		return i.optional
+	}
+	return false
+}
+
+// namedDefaultsFor returns the defaults of optional named parameters set on i
+// if it is an sqlx.DB, Tx, Conn or NamedStmt.
+func namedDefaultsFor(i any) map[string]any {
+	switch i := i.(type) {
+	case *DB:
+		return i.namedDefaults
+	case *Tx:
+		return i.namedDefaults
+	case *Conn:
+		return i.namedDefaults
+	case *NamedStmt:
+		return i.defaults
+	}
+	return nil
+}
+
+// optionalFor returns arg wrapped with Optional if i makes all named
+// parameters optional, and given the defaults of i if it has any.
+func optionalFor(i any, arg any) any {
+	all, defaults := isOptionalNamed(i), namedDefaultsFor(i)
+	if !all && len(defaults) == 0 {
+		return arg
+	}
+	o, ok := arg.(OptionalArgs)
+	if !ok {
+		o = OptionalArgs{arg: arg}
+	}
+	o.all = o.all || all
+	o.defaults = mergeDefaults(defaults, o.defaults)
+	return o
+}
@@ -0,0 +1,83 @@
+package sqlx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// resolveName finds the value for a dotted parameter name in v, walking
+// through structs, maps with string keys, pointers and interfaces, so that
+// :user.address.city can be bound from a map[string]any holding decoded JSON
+// as well as from a struct.  Map keys and struct paths containing dots are
+// matched before their parts are.
+//
+// A nil pointer or interface along the way resolves to the zero Value, which
+// binds as NULL.  The FieldInfo of the last struct field walked through is
+// returned if the value is one.
+func resolveName(v reflect.Value, name string, m *reflectx.Mapper) (reflect.Value, *reflectx.FieldInfo, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, nil, true
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		for i := len(name); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+			elem := v.MapIndex(reflect.ValueOf(name[:i]).Convert(v.Type().Key()))
+			if !elem.IsValid() {
+				continue
+			}
+			if i == len(name) {
+				return elem, nil, true
+			}
+			return resolveName(elem, name[i+1:], m)
+		}
+	case reflect.Struct:
+		tm := m.TypeMap(v.Type())
+		for i := len(name); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+			fi := tm.GetByPath(name[:i])
+			if fi == nil {
+				continue
+			}
+			f := reflectx.FieldByIndexesReadOnly(v, fi.Index)
+			if i == len(name) {
+				return f, fi, true
+			}
+			return resolveName(f, name[i+1:], m)
+		}
+	}
+	return reflect.Value{}, nil, false
+}
+
+// bindValue returns the argument to bind for the value of the named
+// parameter name, with fi being the struct field it was read from, if any.
+func bindValue(name string, val reflect.Value, fi *reflectx.FieldInfo, m *reflectx.Mapper) (any, error) {
+	if !val.IsValid() {
+		return nil, nil
+	}
+	if fi != nil {
+		if _, ok := fi.Options["json"]; ok {
+			dv, err := encodeJSON(val)
+			if err != nil {
+				return nil, fmt.Errorf("could not encode %s as JSON: %w", name, err)
+			}
+			return dv, nil
+		}
+	}
+	if c, ok := converterFor(m, val.Type()); ok && c.Value != nil {
+		dv, err := c.value(val)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert %s: %w", name, err)
+		}
+		return dv, nil
+	}
+	return val.Interface(), nil
+}
@@ -1,8 +1,11 @@
 package sqlx
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/i9si-sistemas/sqlx/binder"
@@ -55,16 +58,50 @@ func TestCompileQuery(t *testing.T) {
 			T: `SELECT @name := "name", @p1, @p2, @p3`,
 			V: []string{"age", "first", "last"},
 		},
-		/* This unicode awareness test sadly fails, because of our byte-wise worldview.
-		 * We could certainly iterate by Rune instead, though it's a great deal slower,
-		 * it's probably the RightWay(tm)
+		// casts are understood natively, including right after a parameter
+		{
+			Q: `SELECT :id::uuid, created::date FROM t WHERE tags = :tags::text[]`,
+			R: `SELECT ?::uuid, created::date FROM t WHERE tags = ?::text[]`,
+			D: `SELECT $1::uuid, created::date FROM t WHERE tags = $2::text[]`,
+			T: `SELECT @p1::uuid, created::date FROM t WHERE tags = @p2::text[]`,
+			N: `SELECT :id::uuid, created::date FROM t WHERE tags = :tags::text[]`,
+			V: []string{"id", "tags"},
+		},
+		// longer runs of colons keep the '::' escape
+		{
+			Q: `SELECT created::::date, $$ x::::int $$, :::id FROM t`,
+			R: `SELECT created::date, $$ x::int $$, :? FROM t`,
+			D: `SELECT created::date, $$ x::int $$, :$1 FROM t`,
+			T: `SELECT created::date, $$ x::int $$, :@p1 FROM t`,
+			N: `SELECT created::date, $$ x::int $$, ::id FROM t`,
+			V: []string{"id"},
+		},
+		// in every kind of quotes, each '::' stands for a ':'
+		{
+			Q: "SELECT 'a::b', \"a::b\", `a::b`, $$ a::b $$, $q$ a::::b $q$, '::1'::inet FROM t",
+			R: "SELECT 'a:b', \"a:b\", `a:b`, $$ a:b $$, $q$ a::b $q$, ':1'::inet FROM t",
+			D: "SELECT 'a:b', \"a:b\", `a:b`, $$ a:b $$, $q$ a::b $q$, ':1'::inet FROM t",
+			T: "SELECT 'a:b', \"a:b\", `a:b`, $$ a:b $$, $q$ a::b $q$, ':1'::inet FROM t",
+			N: "SELECT 'a:b', \"a:b\", `a:b`, $$ a:b $$, $q$ a::b $q$, ':1'::inet FROM t",
+			V: []string{},
+		},
+		// comments, escaped quotes and dollar-quoted strings are skipped
+		{
+			Q: "SELECT 'it''s :no', `:no`, $$ :no $$, $fn$ :no $$ $fn$ -- :no\n/* :no /* :no */ */ FROM t WHERE a = :yes",
+			R: "SELECT 'it''s :no', `:no`, $$ :no $$, $fn$ :no $$ $fn$ -- :no\n/* :no /* :no */ */ FROM t WHERE a = ?",
+			D: "SELECT 'it''s :no', `:no`, $$ :no $$, $fn$ :no $$ $fn$ -- :no\n/* :no /* :no */ */ FROM t WHERE a = $1",
+			T: "SELECT 'it''s :no', `:no`, $$ :no $$, $fn$ :no $$ $fn$ -- :no\n/* :no /* :no */ */ FROM t WHERE a = @p1",
+			N: "SELECT 'it''s :no', `:no`, $$ :no $$, $fn$ :no $$ $fn$ -- :no\n/* :no /* :no */ */ FROM t WHERE a = :yes",
+			V: []string{"yes"},
+		},
 		{
 			Q: `INSERT INTO foo (a,b,c,d) VALUES (:あ, :b, :キコ, :名前)`,
 			R: `INSERT INTO foo (a,b,c,d) VALUES (?, ?, ?, ?)`,
 			D: `INSERT INTO foo (a,b,c,d) VALUES ($1, $2, $3, $4)`,
-			N: []string{"name", "age", "first", "last"},
+			T: `INSERT INTO foo (a,b,c,d) VALUES (@p1, @p2, @p3, @p4)`,
+			N: `INSERT INTO foo (a,b,c,d) VALUES (:あ, :b, :キコ, :名前)`,
+			V: []string{"あ", "b", "キコ", "名前"},
 		},
-		*/
 	}
 
 	for _, test := range table {
@@ -124,13 +161,528 @@ func (t Test) Errorf(err error, format string, args ...any) {
 }
 
 func TestEscapedColons(t *testing.T) {
-	t.Skip("not sure it is possible to support this in general case without an SQL parser")
 	var qs = `SELECT * FROM testtable WHERE timeposted BETWEEN (now() AT TIME ZONE 'utc') AND
 	(now() AT TIME ZONE 'utc') - interval '01:30:00') AND name = '\'this is a test\'' and id = :id`
-	_, _, err := compileNamedQuery([]byte(qs), binder.DOLLAR)
+	q, names, err := compileNamedQuery([]byte(qs), binder.DOLLAR)
 	if err != nil {
 		t.Error("Didn't handle colons correctly when inside a string")
 	}
+	if len(names) != 1 || names[0] != "id" || !strings.HasSuffix(q, "id = $1") {
+		t.Errorf("unexpected compilation: %s %v", q, names)
+	}
+
+	// postgres only allows backslash escapes in E'' strings
+	q, names, err = compileNamedQuery([]byte(`SELECT 'C:\', E'\':no', :id`), binder.DOLLAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `SELECT 'C:\', E'\':no', $1` || len(names) != 1 {
+		t.Errorf("unexpected compilation: %s %v", q, names)
+	}
+}
+
+func TestCompileQueryErrors(t *testing.T) {
+	table := []struct {
+		Q, Err string
+	}{
+		{Q: "SELECT 'abc", Err: "unterminated string literal at line 1, column 8"},
+		{Q: "SELECT :a,\n  \"ab", Err: "unterminated quoted identifier at line 2, column 3"},
+		{Q: "SELECT ✓, /* :a", Err: "unterminated comment at line 1, column 11"},
+		{Q: "SELECT $body$ :a $$", Err: "unterminated dollar-quoted string at line 1, column 8"},
+	}
+	for _, test := range table {
+		_, _, err := compileNamedQuery([]byte(test.Q), binder.DOLLAR)
+		if err == nil || err.Error() != test.Err {
+			t.Errorf("expected %q for %q, got %v", test.Err, test.Q, err)
+		}
+	}
+}
+
+func TestBindNamedSlices(t *testing.T) {
+	type filter struct {
+		Names []string `db:"names"`
+		Code  int      `db:"code"`
+		Data  []byte   `db:"data"`
+	}
+	f := filter{Names: []string{"a", "b", "c"}, Code: 1, Data: []byte("x")}
+	query := "SELECT * FROM t WHERE name IN (:names) AND code = :code AND data = :data"
+
+	table := []struct {
+		BindType int
+		Expect   string
+	}{
+		{binder.QUESTION, "SELECT * FROM t WHERE name IN (?, ?, ?) AND code = ? AND data = ?"},
+		{binder.DOLLAR, "SELECT * FROM t WHERE name IN ($1, $2, $3) AND code = $4 AND data = $5"},
+		{binder.AT, "SELECT * FROM t WHERE name IN (@p1, @p2, @p3) AND code = @p4 AND data = @p5"},
+		{binder.NAMED, "SELECT * FROM t WHERE name IN (:names_1, :names_2, :names_3) AND code = :code AND data = :data"},
+	}
+	for _, test := range table {
+		q, args, err := BindNamed(test.BindType, query, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q != test.Expect {
+			t.Errorf("expected %s, got %s", test.Expect, q)
+		}
+		if len(args) != 5 || args[0] != "a" || args[2] != "c" || args[3] != 1 {
+			t.Errorf("unexpected args: %#v", args)
+		}
+		if _, ok := args[4].([]byte); !ok {
+			t.Errorf("expected []byte to be bound as is, got %#v", args[4])
+		}
+	}
+
+	// only whole IN lists are expanded
+	q, args, err := BindNamed(binder.DOLLAR, "SELECT * FROM t WHERE a NOT IN ( :ids ) AND b = ANY(:any) AND c IN (:one, 2)",
+		map[string]any{"ids": []int{1, 2}, "any": []int64{3, 4}, "one": []int{5, 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT * FROM t WHERE a NOT IN ( $1, $2 ) AND b = ANY($3) AND c IN ($4, 2)" || len(args) != 4 {
+		t.Errorf("unexpected binding: %s %#v", q, args)
+	}
+	if _, ok := args[2].([]int64); !ok {
+		t.Errorf("expected a slice outside of an IN list to be bound as is, got %#v", args[2])
+	}
+
+	_, _, err = BindNamed(binder.QUESTION, query, filter{Code: 1})
+	if err == nil || err.Error() != "empty slice passed for named parameter names" {
+		t.Errorf("expected an empty slice error, got %v", err)
+	}
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :id IN (:ids)", map[string]any{"id": 1, "ids": []int{}})
+	if err == nil {
+		t.Error("expected an error for an empty slice in a map")
+	}
+	_, args, err = BindNamed(binder.QUESTION, "SELECT :ids", map[string]any{"ids": []int{}})
+	if err != nil || len(args) != 1 {
+		t.Errorf("expected an empty slice outside of an IN list to be bound as is, got %#v, %v", args, err)
+	}
+}
+
+func TestBindNamedPaths(t *testing.T) {
+	type address struct {
+		City string `db:"city"`
+	}
+	type user struct {
+		Name    string         `db:"name"`
+		Address *address       `db:"address"`
+		Meta    map[string]any `db:"meta"`
+	}
+
+	var body map[string]any
+	err := json.Unmarshal([]byte(`{"user": {"name": "Ana", "address": {"city": "Recife"}, "tags": null}}`), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := "SELECT :user.name, :user.address.city, :user.tags"
+	q, args, err := BindNamed(binder.QUESTION, query, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT ?, ?, ?" || len(args) != 3 || args[0] != "Ana" || args[1] != "Recife" || args[2] != nil {
+		t.Errorf("unexpected binding: %s %#v", q, args)
+	}
+
+	// structs within maps, and keys containing dots
+	arg := map[string]any{
+		"user":      &user{Name: "Bia", Meta: map[string]any{"level": 3}},
+		"user.name": "Dots",
+	}
+	_, args, err = BindNamed(binder.QUESTION, "SELECT :user.name, :user.address.city, :user.meta.level", arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != "Dots" || args[1] != nil || args[2] != 3 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	// nil pointers in structs bind as NULL, and maps in structs are walked
+	_, args, err = BindNamed(binder.QUESTION, "SELECT :address.city, :meta.level", user{Meta: map[string]any{"level": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != nil || args[1] != 1 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :user.address.zip", body)
+	if err == nil {
+		t.Error("expected an error for a missing nested name")
+	}
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :meta.missing", user{Meta: map[string]any{}})
+	if err == nil {
+		t.Error("expected an error for a missing key in a struct's map")
+	}
+}
+
+func TestNamedArgs(t *testing.T) {
+	type request struct {
+		Name     string `db:"name"`
+		TenantID int    `db:"tenant_id"`
+	}
+	req := request{Name: "Ana", TenantID: 99}
+	extra := map[string]any{"tenant_id": 1, "ids": []int{1, 2}}
+
+	q, args, err := BindNamed(binder.DOLLAR, "SELECT :name, :tenant_id FROM t WHERE id IN (:ids)", NamedArgs(&req, extra))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT $1, $2 FROM t WHERE id IN ($3, $4)" || len(args) != 4 || args[0] != "Ana" || args[1] != 1 || args[3] != 2 {
+		t.Errorf("unexpected binding: %s %#v", q, args)
+	}
+
+	_, args, err = BindNamed(binder.QUESTION, "SELECT :tenant_id", NamedArgs(extra, req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != 99 {
+		t.Errorf("expected the last source to win, got %#v", args)
+	}
+
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :name, :tenant_id", NamedArgs(req, extra).Strict())
+	if err == nil || err.Error() != "name tenant_id found in both source 0 and source 1" {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :name", NamedArgs(req, extra).Strict())
+	if err != nil {
+		t.Errorf("expected no conflict, got %v", err)
+	}
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :missing", NamedArgs(req, extra))
+	if err == nil {
+		t.Error("expected an error for a missing name")
+	}
+}
+
+func TestBindNamedNative(t *testing.T) {
+	arg := map[string]any{"a": 1, "b.c": 2, "ids": []int{3, 4}, "ids_1": 5}
+	query := "SELECT :a, :b.c, :a FROM t WHERE x IN (:ids) AND y = :ids_1"
+
+	q, args, err := BindNamed(binder.ATNAME, query, arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT @a, @b_c, @a FROM t WHERE x IN (@ids_1, @ids_2) AND y = @ids_1_2" {
+		t.Errorf("unexpected query: %s", q)
+	}
+	expect := []any{sql.Named("a", 1), sql.Named("b_c", 2), sql.Named("ids_1", 3), sql.Named("ids_2", 4), sql.Named("ids_1_2", 5)}
+	if fmt.Sprint(args) != fmt.Sprint(expect) {
+		t.Errorf("expected %v, got %v", expect, args)
+	}
+
+	q, _, err = BindNamed(binder.COLONNAME, "SELECT :1st", map[string]any{"1st": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT :p1st" {
+		t.Errorf("unexpected query: %s", q)
+	}
+	if q := binder.Default.Rebind(binder.ATNAME, "SELECT ?"); q != "SELECT @p1" {
+		t.Errorf("unexpected rebind: %s", q)
+	}
+}
+
+func TestNamedNativeQueries(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		// sqlite3 understands both @name and :name
+		if db.DriverName() != "sqlite3" {
+			t.Skip("native named parameters are only tested on sqlite3")
+		}
+		loadDefaultFixtureContext(ctx, db, t)
+
+		for driverName, bindType := range map[string]int{"sqlite3-at": binder.ATNAME, "sqlite3-colon": binder.COLONNAME} {
+			binder.Default.Driver(driverName, bindType)
+			native := NewDb(db.DB, driverName)
+
+			var people []Person
+			rows, err := native.NamedQueryContext(ctx, "SELECT * FROM person WHERE first_name = :name OR last_name = :name OR first_name IN (:others)",
+				map[string]any{"name": "Jason", "others": []string{"John", "Jack"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = scanAll(rows, &people, false); err != nil {
+				t.Fatal(err)
+			}
+			rows.Close()
+			if len(people) != 2 {
+				t.Errorf("%s: expected 2 people, got %d", driverName, len(people))
+			}
+
+			ns, err := native.PrepareNamedContext(ctx, "SELECT * FROM person WHERE first_name = :first_name")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p Person
+			if err = ns.GetContext(ctx, &p, Person{FirstName: "John"}); err != nil {
+				t.Fatal(err)
+			}
+			ns.Close()
+			if p.LastName != "Doe" {
+				t.Errorf("%s: expected Doe, got %s", driverName, p.LastName)
+			}
+		}
+	})
+}
+
+func TestOptionalNamed(t *testing.T) {
+	query := "UPDATE t SET a = :a?, b = :b, tenant = :test_tenant? WHERE id = :id"
+	q, args, err := BindNamed(binder.DOLLAR, query, map[string]any{"b": 2, "id": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "UPDATE t SET a = $1, b = $2, tenant = $3 WHERE id = $4" {
+		t.Errorf("unexpected query: %s", q)
+	}
+	if len(args) != 4 || args[0] != nil || args[1] != 2 || args[2] != nil || args[3] != 3 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+	if _, _, err = BindNamed(binder.DOLLAR, query, map[string]any{"id": 3}); err == nil {
+		t.Error("expected an error for a missing required name")
+	}
+
+	type row struct {
+		ID int `db:"id"`
+	}
+	_, args, err = BindNamed(binder.QUESTION, query, Optional(row{ID: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != nil || args[1] != nil || args[2] != nil || args[3] != 3 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	defaults := map[string]any{"test_tenant": 7}
+	_, args, err = BindNamed(binder.QUESTION, query, Optional(row{ID: 3}).WithDefaults(defaults))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != nil || args[1] != nil || args[2] != 7 || args[3] != 3 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+	// defaults of the call win over those of the DB
+	arg := optionalFor(&DB{namedDefaults: map[string]any{"test_tenant": 1, "a": 2}}, Optional(row{ID: 3}).WithDefaults(defaults))
+	_, args, err = BindNamed(binder.QUESTION, query, arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != 2 || args[2] != 7 || args[3] != 3 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	_, args, err = BindNamed(binder.QUESTION, "INSERT INTO t VALUES (:id, :b)", Optional([]row{{ID: 1}, {ID: 2}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 4 || args[0] != 1 || args[1] != nil || args[2] != 2 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	// maps which can't hold any value fail as they do unwrapped
+	_, _, err = BindNamed(binder.QUESTION, "SELECT :a", Optional(map[string]string{"a": "x"}))
+	if err == nil || !strings.Contains(err.Error(), "unsupported map type") {
+		t.Errorf("expected an unsupported map error, got %v", err)
+	}
+	_, err = bindAnyArgs([]string{"a"}, nil, Optional(map[string]string{"a": "x"}), mapper())
+	if err == nil || !strings.Contains(err.Error(), "unsupported map type") {
+		t.Errorf("expected an unsupported map error from bindAnyArgs, got %v", err)
+	}
+
+	_, args, err = BindNamed(binder.QUESTION, "SELECT :id, :a", Optional(NamedArgs(row{ID: 1})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != 1 || args[1] != nil {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	// a nil arg has nothing to bind from, so every name takes its default
+	_, args, err = BindNamed(binder.DOLLAR, "SELECT :a?, :test_tenant", Optional(nil).WithDefaults(defaults))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[0] != nil || args[1] != 7 {
+		t.Errorf("unexpected args: %#v", args)
+	}
+	args, err = bindAnyArgs([]string{"a"}, nil, Optional(nil), mapper())
+	if err != nil || len(args) != 1 || args[0] != nil {
+		t.Errorf("unexpected args: %#v %v", args, err)
+	}
+	if _, _, err = BindNamed(binder.DOLLAR, "SELECT :a", optionalFor(&DB{namedDefaults: defaults}, nil)); err == nil {
+		t.Error("expected an error for a missing required name")
+	}
+}
+
+func TestOptionalNamedQueries(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		query := "INSERT INTO nullperson (first_name, last_name, email) VALUES (:first_name, :last_name, :email)"
+		if _, err := db.NamedExecContext(ctx, query, map[string]any{"first_name": "Ana"}); err == nil {
+			t.Error("expected an error for missing names")
+		}
+
+		optional := db.OptionalNamed()
+		if _, err := optional.NamedExecContext(ctx, query, map[string]any{"first_name": "Ana"}); err != nil {
+			t.Fatal(err)
+		}
+		ns, err := optional.PrepareNamedContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ns.Close()
+		if _, err = ns.ExecContext(ctx, map[string]any{"first_name": "Bia", "email": "bia@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var people []struct {
+			FirstName string         `db:"first_name"`
+			LastName  sql.NullString `db:"last_name"`
+			Email     sql.NullString `db:"email"`
+		}
+		if err = db.SelectContext(ctx, &people, "SELECT * FROM nullperson ORDER BY first_name"); err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 || people[0].LastName.Valid || people[0].Email.Valid || !people[1].Email.Valid {
+			t.Errorf("unexpected people: %#v", people)
+		}
+
+		tenant := optional.NamedDefaults(map[string]any{"last_name": "Lee"})
+		if _, err = db.NamedExecContext(ctx, query, map[string]any{"first_name": "Cid"}); err == nil {
+			t.Error("expected an error for missing names")
+		}
+		tx, err := tenant.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		if _, err = tx.NamedExecContext(ctx, query, map[string]any{"first_name": "Cid"}); err != nil {
+			t.Fatal(err)
+		}
+		var last sql.NullString
+		if err = tx.GetContext(ctx, &last, "SELECT last_name FROM nullperson WHERE first_name = 'Cid'"); err != nil {
+			t.Fatal(err)
+		}
+		if last.String != "Lee" {
+			t.Errorf("expected the default last name, got %#v", last)
+		}
+		ns, err = tx.PrepareNamedContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ns.Close()
+		if _, err = ns.ExecContext(ctx, map[string]any{"first_name": "Dan"}); err != nil {
+			t.Fatal(err)
+		}
+		if err = tx.GetContext(ctx, &last, "SELECT last_name FROM nullperson WHERE first_name = 'Dan'"); err != nil {
+			t.Fatal(err)
+		}
+		if last.String != "Lee" {
+			t.Errorf("expected the default last name from the statement, got %#v", last)
+		}
+
+		// a nil arg binds defaults and NULL only
+		if _, err = tx.NamedExecContext(ctx, query, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ns.ExecContext(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		if err = tx.GetContext(ctx, &count, "SELECT count(*) FROM nullperson WHERE first_name IS NULL AND last_name = 'Lee'"); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 people bound from defaults, got %d", count)
+		}
+	})
+}
+
+func TestNamedSliceQueries(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		type filter struct {
+			Names []string `db:"names"`
+		}
+		query := "SELECT * FROM person WHERE first_name IN (:names) ORDER BY first_name"
+
+		rows, err := db.NamedQueryContext(ctx, query, filter{Names: []string{"Jason", "John", "Jack"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var people []Person
+		if err = scanAll(rows, &people, false); err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if len(people) != 2 {
+			t.Errorf("expected 2 people, got %d", len(people))
+		}
+
+		res, err := db.NamedExecContext(ctx, "UPDATE person SET email = :email WHERE last_name IN (:last)",
+			map[string]any{"email": "x@y.z", "last": []string{"Doe", "Nobody"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			t.Errorf("expected 1 row affected, got %d", n)
+		}
+
+		ns, err := db.PrepareNamedContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ns.Close()
+		for _, names := range [][]string{{"John"}, {"Jason", "John"}, {"John", "Jason"}, {"Jason", "John", "Jack"}} {
+			people = nil
+			if err = ns.SelectContext(ctx, &people, filter{Names: names}); err != nil {
+				t.Fatal(err)
+			}
+			if len(people) != min(len(names), 2) {
+				t.Errorf("unexpected people for %v: %#v", names, people)
+			}
+		}
+		// single names use the original statement
+		if len(ns.shapes.stmts) != 2 {
+			t.Errorf("expected 2 prepared shapes, got %d", len(ns.shapes.stmts))
+		}
+
+		people = nil
+		err = ns.SelectContext(ctx, &people, NamedArgs(filter{Names: []string{"Jason"}}, map[string]any{"names": []string{"Jason", "John"}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 {
+			t.Errorf("expected 2 people from merged args, got %d", len(people))
+		}
+
+		// statements for the least recently used lengths are closed
+		names := []string{"John"}
+		for range maxNamedShapes + 2 {
+			names = append(names, "Jason")
+			if err = ns.SelectContext(ctx, &people, filter{Names: names}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(ns.shapes.stmts) != maxNamedShapes || ns.shapes.order.Len() != maxNamedShapes {
+			t.Errorf("expected %d prepared shapes, got %d", maxNamedShapes, len(ns.shapes.stmts))
+		}
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		var p Person
+		err = tx.NamedStmtContext(ctx, ns).GetContext(ctx, &p, filter{Names: []string{"Jack", "John"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.LastName != "Doe" {
+			t.Errorf("expected Doe, got %s", p.LastName)
+		}
+
+		if err = ns.SelectContext(ctx, &people, filter{}); err == nil {
+			t.Error("expected an error for an empty slice")
+		}
+	})
 }
 
 func TestNamedQueries(t *testing.T) {
@@ -0,0 +1,173 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// An UnresolvedName is a parameter of a named query which an arg type has no
+// value for, with the closest name it has, if any is close enough.
+type UnresolvedName struct {
+	Name       string
+	Suggestion string
+}
+
+// A NamedValidationError lists the parameters of a named query which cannot
+// be bound from an arg type.
+type NamedValidationError struct {
+	Type       reflect.Type
+	Unresolved []UnresolvedName
+}
+
+func (e *NamedValidationError) Error() string {
+	parts := make([]string, len(e.Unresolved))
+	for i, u := range e.Unresolved {
+		parts[i] = u.Name
+		if u.Suggestion != "" {
+			parts[i] += fmt.Sprintf(" (did you mean %s?)", u.Suggestion)
+		}
+	}
+	return fmt.Sprintf("sqlx: could not find names in %v: %s", e.Type, strings.Join(parts, ", "))
+}
+
+// ValidateNamed checks that every parameter of a named query can be bound from
+// an arg of type argType, so that misspelled names can be caught at startup
+// rather than when the query runs.  Names are resolved as NamedExec resolves
+// them, through the default Mapper for structs, and through the element type
+// for slices.  Names going through maps or interfaces, and optional :name?
+// parameters, are assumed to resolve.  The query is compiled for bindType.
+//
+// The error, if the query compiles, is a *NamedValidationError listing all of
+// the names which do not resolve, with suggestions.
+func ValidateNamed(query string, argType reflect.Type, bindType int) error {
+	return validateNamed(query, argType, bindType, mapper())
+}
+
+// MustValidateNamed is like ValidateNamed but panics on error, for checks run
+// when initializing a package.
+func MustValidateNamed(query string, argType reflect.Type, bindType int) {
+	if err := ValidateNamed(query, argType, bindType); err != nil {
+		panic(err)
+	}
+}
+
+func validateNamed(query string, argType reflect.Type, bindType int, m *reflectx.Mapper) error {
+	if argType == nil {
+		return errors.New("sqlx: cannot validate named query against a nil type")
+	}
+	q, err := parseNamedQuery(query, bindType)
+	if err != nil {
+		return err
+	}
+	t := reflectx.Deref(argType)
+	if k := t.Kind(); k == reflect.Slice || k == reflect.Array {
+		t = reflectx.Deref(t.Elem())
+	}
+
+	var unresolved []UnresolvedName
+	for i, name := range q.names {
+		if q.optional[i] || resolvesType(t, name, m) || containsName(unresolved, name) {
+			continue
+		}
+		unresolved = append(unresolved, UnresolvedName{Name: name, Suggestion: suggestName(t, name, m)})
+	}
+	if unresolved != nil {
+		return &NamedValidationError{Type: argType, Unresolved: unresolved}
+	}
+	return nil
+}
+
+// resolvesType reports whether resolveName could find name in a value of
+// type t.
+func resolvesType(t reflect.Type, name string, m *reflectx.Mapper) bool {
+	t = reflectx.Deref(t)
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Map:
+		// the keys are only known when binding
+		return t.Key().Kind() == reflect.String
+	case reflect.Struct:
+		tm := m.TypeMap(t)
+		for i := len(name); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+			fi := tm.GetByPath(name[:i])
+			if fi == nil {
+				continue
+			}
+			return i == len(name) || resolvesType(fi.Field.Type, name[i+1:], m)
+		}
+	}
+	return false
+}
+
+func containsName(unresolved []UnresolvedName, name string) bool {
+	for _, u := range unresolved {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestName returns the path of t closest to name, if it is close enough
+// to be a likely misspelling.
+func suggestName(t reflect.Type, name string, m *reflectx.Mapper) string {
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	var paths []string
+	for path := range m.TypeMap(t).Paths {
+		paths = append(paths, path)
+	}
+	// sort for a stable choice between paths at the same distance
+	sort.Strings(paths)
+
+	best, bestDist := "", max(len(name)/3, 2)+1
+	for _, path := range paths {
+		if d := editDistance(name, path); d < bestDist {
+			best, bestDist = path, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// validateSample validates query for p against the type of sample, which may
+// also be a reflect.Type, as PrepareNamedValidated does.
+func validateSample(p Binder, query string, sample any) error {
+	if isOptionalNamed(p) {
+		return nil
+	}
+	t, ok := sample.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(sample)
+	}
+	return validateNamed(query, t, binder.Default.Type(p.DriverName()), mapperFor(p))
+}
@@ -0,0 +1,87 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/i9si-sistemas/sqlx/binder"
+)
+
+func TestValidateNamed(t *testing.T) {
+	type address struct {
+		City string `db:"city"`
+	}
+	type user struct {
+		FirstName string         `db:"first_name"`
+		Email     string         `db:"email"`
+		Address   *address       `db:"address"`
+		Meta      map[string]any `db:"meta"`
+	}
+	typ := reflect.TypeOf(user{})
+
+	err := ValidateNamed("SELECT :first_name, :email, :address.city, :meta.anything, :nickname?", typ, binder.DOLLAR)
+	if err != nil {
+		t.Errorf("expected the query to validate, got %v", err)
+	}
+	if err = ValidateNamed("INSERT INTO t VALUES (:email)", reflect.TypeOf([]*user{}), binder.QUESTION); err != nil {
+		t.Errorf("expected slices to validate against their elements, got %v", err)
+	}
+	if err = ValidateNamed("SELECT :anything", reflect.TypeOf(map[string]any{}), binder.QUESTION); err != nil {
+		t.Errorf("expected maps to validate, got %v", err)
+	}
+
+	err = ValidateNamed("SELECT :emial, :frist_name, :address.cty, :zzz, :emial", reflect.TypeOf(&user{}), binder.DOLLAR)
+	var verr *NamedValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a NamedValidationError, got %v", err)
+	}
+	expect := []UnresolvedName{
+		{Name: "emial", Suggestion: "email"},
+		{Name: "frist_name", Suggestion: "first_name"},
+		{Name: "address.cty", Suggestion: "address.city"},
+		{Name: "zzz"},
+	}
+	if !reflect.DeepEqual(verr.Unresolved, expect) {
+		t.Errorf("expected %v, got %v", expect, verr.Unresolved)
+	}
+	msg := "sqlx: could not find names in *sqlx.user: emial (did you mean email?), frist_name (did you mean first_name?), address.cty (did you mean address.city?), zzz"
+	if err.Error() != msg {
+		t.Errorf("unexpected message: %s", err)
+	}
+
+	if err = ValidateNamed("SELECT 'abc", typ, binder.DOLLAR); err == nil || errors.As(err, &verr) {
+		t.Errorf("expected a compile error, got %v", err)
+	}
+	if err = ValidateNamed("SELECT :email", nil, binder.DOLLAR); err == nil || errors.As(err, &verr) {
+		t.Errorf("expected an error for a nil type, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustValidateNamed to panic")
+		}
+	}()
+	MustValidateNamed("SELECT :emial", typ, binder.DOLLAR)
+}
+
+func TestPrepareNamedSample(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		_, err := db.PrepareNamedValidatedContext(ctx, "SELECT * FROM person WHERE first_name = :frist_name", Person{})
+		var verr *NamedValidationError
+		if !errors.As(err, &verr) || verr.Unresolved[0].Suggestion != "first_name" {
+			t.Errorf("expected a NamedValidationError, got %v", err)
+		}
+
+		if _, err = db.PrepareNamedValidated("SELECT * FROM person WHERE first_name = :first_name", nil); err == nil {
+			t.Error("expected an error for a nil sample")
+		}
+
+		ns, err := db.PrepareNamedValidated("SELECT * FROM person WHERE first_name = :first_name", reflect.TypeOf(&Person{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ns.Close()
+	})
+}
@@ -0,0 +1,291 @@
+package sqlx
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColTypeScanner is an interface used by NormalizedMapScan and
+// NormalizedSliceScan
+type ColTypeScanner interface {
+	ColScanner
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+// NormalizedSliceScan is like SliceScan, but converts each value according to
+// the column's type so that every driver returns the same Go types:  int64 for
+// integers, float64 for floating point numbers, bool for booleans, time.Time
+// for dates and timestamps, []byte for binary data, string for everything else
+// and nil for NULL.  Decimal and money values are strings too, so that no
+// precision is lost.  Unsigned integers too large for an int64 fail to scan.
+func NormalizedSliceScan(r ColTypeScanner) ([]any, error) {
+	// column types must be read before scanning, as scanning a Row closes it.
+	types, err := r.ColumnTypes()
+	if err != nil {
+		return []any{}, err
+	}
+	values, err := SliceScan(r)
+	if err != nil {
+		return values, err
+	}
+	for i, ct := range types {
+		if values[i], err = normalizeValue(values[i], ct); err != nil {
+			return values, err
+		}
+	}
+	return values, nil
+}
+
+// NormalizedMapScan is like MapScan, but converts each value according to
+// the column's type as NormalizedSliceScan does.
+func NormalizedMapScan(r ColTypeScanner, dest map[string]any) error {
+	columns, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	values, err := NormalizedSliceScan(r)
+	if err != nil {
+		return err
+	}
+	for i, column := range columns {
+		dest[column] = values[i]
+	}
+	return nil
+}
+
+// NormalizedSliceScan using this Rows.
+func (r *Rows) NormalizedSliceScan() ([]any, error) {
+	return NormalizedSliceScan(r)
+}
+
+// NormalizedMapScan using this Rows.
+func (r *Rows) NormalizedMapScan(dest map[string]any) error {
+	return NormalizedMapScan(r, dest)
+}
+
+// NormalizedSliceScan using this Row.
+func (r *Row) NormalizedSliceScan() ([]any, error) {
+	return NormalizedSliceScan(r)
+}
+
+// NormalizedMapScan using this Row.
+func (r *Row) NormalizedMapScan(dest map[string]any) error {
+	return NormalizedMapScan(r, dest)
+}
+
+// The kinds of values produced by the normalizing scans.
+const (
+	normalString = iota
+	normalInt
+	normalFloat
+	normalBool
+	normalTime
+	normalBytes
+	normalDecimal
+)
+
+// normalKind returns the kind of value a column of type ct normalizes to.  It
+// goes by the database type name, falling back on the driver's scan type.
+func normalKind(ct *sql.ColumnType) int {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(strings.TrimPrefix(name, "UNSIGNED "))
+
+	switch name {
+	case "BOOL", "BOOLEAN":
+		return normalBool
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT",
+		"INT2", "INT4", "INT8", "SERIAL", "BIGSERIAL", "SMALLSERIAL", "YEAR":
+		return normalInt
+	case "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "DOUBLE PRECISION", "REAL":
+		return normalFloat
+	case "DECIMAL", "NUMERIC", "MONEY":
+		return normalDecimal
+	case "DATE", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return normalTime
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA", "BINARY", "VARBINARY":
+		return normalBytes
+	case "":
+	default:
+		return normalString
+	}
+
+	// sqlite reports no type name for expressions, so look at the scan type.
+	t := ct.ScanType()
+	if t == nil {
+		return normalString
+	}
+	switch t {
+	case reflect.TypeFor[time.Time](), reflect.TypeFor[sql.NullTime]():
+		return normalTime
+	case reflect.TypeFor[sql.NullInt64](), reflect.TypeFor[sql.NullInt32](), reflect.TypeFor[sql.NullInt16]():
+		return normalInt
+	case reflect.TypeFor[sql.NullFloat64]():
+		return normalFloat
+	case reflect.TypeFor[sql.NullBool]():
+		return normalBool
+	case reflect.TypeFor[[]byte](), reflect.TypeFor[sql.RawBytes]():
+		return normalBytes
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return normalInt
+	case reflect.Float32, reflect.Float64:
+		return normalFloat
+	case reflect.Bool:
+		return normalBool
+	}
+	return normalString
+}
+
+// timeFormats are the layouts tried when a date or timestamp arrives as text.
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	time.RFC3339Nano,
+	"2006-01-02",
+}
+
+// normalizeValue converts v, as returned by the driver for a column of type
+// ct, into the Go type for the column's normalized kind.
+func normalizeValue(v any, ct *sql.ColumnType) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	n, err := normalize(v, normalKind(ct))
+	if err != nil {
+		return nil, fmt.Errorf("normalizing column %s of type %s: %w", ct.Name(), ct.DatabaseTypeName(), err)
+	}
+	return n, nil
+}
+
+// normalize converts the non-nil v into the Go type for kind.
+func normalize(v any, kind int) (any, error) {
+	// drivers return text as either string or []byte
+	var text string
+	var isText bool
+	switch t := v.(type) {
+	case []byte:
+		if kind == normalBytes {
+			return t, nil
+		}
+		text, isText = string(t), true
+	case string:
+		text, isText = t, true
+	}
+
+	var err error
+	switch kind {
+	case normalInt:
+		switch t := v.(type) {
+		case int64:
+			return t, nil
+		case bool:
+			if t {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+		if isText {
+			var n int64
+			n, err = strconv.ParseInt(text, 10, 64)
+			if err == nil {
+				return n, nil
+			}
+			if _, uerr := strconv.ParseUint(text, 10, 64); uerr == nil {
+				err = fmt.Errorf("value %s overflows int64", text)
+			}
+		} else if rv := reflect.ValueOf(v); rv.CanInt() {
+			return rv.Int(), nil
+		} else if rv.CanUint() {
+			if rv.Uint() > math.MaxInt64 {
+				return nil, fmt.Errorf("value %d overflows int64", rv.Uint())
+			}
+			return int64(rv.Uint()), nil
+		}
+	case normalFloat:
+		switch t := v.(type) {
+		case float64:
+			return t, nil
+		case float32:
+			return float64(t), nil
+		case int64:
+			return float64(t), nil
+		}
+		if isText {
+			var f float64
+			f, err = strconv.ParseFloat(text, 64)
+			if err == nil {
+				return f, nil
+			}
+		}
+	case normalBool:
+		switch t := v.(type) {
+		case bool:
+			return t, nil
+		case int64:
+			return t != 0, nil
+		}
+		if isText {
+			var b bool
+			b, err = strconv.ParseBool(text)
+			if err == nil {
+				return b, nil
+			}
+		}
+	case normalTime:
+		if t, ok := v.(time.Time); ok {
+			return t, nil
+		}
+		if isText {
+			for _, layout := range timeFormats {
+				if t, perr := time.Parse(layout, text); perr == nil {
+					return t, nil
+				}
+			}
+			err = fmt.Errorf("unrecognized time format %q", text)
+		}
+	case normalBytes:
+		if isText {
+			return []byte(text), nil
+		}
+	case normalDecimal:
+		if isText {
+			return text, nil
+		}
+		// sqlite stores decimals as integers or floating point numbers
+		switch t := v.(type) {
+		case int64:
+			return strconv.FormatInt(t, 10), nil
+		case float64:
+			return strconv.FormatFloat(t, 'f', -1, 64), nil
+		}
+	default:
+		if isText {
+			return text, nil
+		}
+		switch t := v.(type) {
+		case time.Time:
+			return t, nil
+		case bool, int64, float64:
+			return t, nil
+		}
+		return fmt.Sprint(v), nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("unsupported value of type %T", v)
+	}
+	return nil, err
+}
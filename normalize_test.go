@@ -0,0 +1,110 @@
+package sqlx
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizedScan(t *testing.T) {
+	var schema = Schema{
+		create: `
+CREATE TABLE measure (
+	id integer,
+	label text,
+	reading real,
+	price numeric(10,2),
+	taken_at timestamp NULL,
+	active boolean
+);`,
+		drop: `drop table measure;`,
+	}
+
+	RunWithSchemaContext(context.Background(), schema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		db.MustExecContext(ctx, db.Rebind("INSERT INTO measure (id, label, reading, price, taken_at, active) VALUES (?, ?, ?, ?, ?, ?)"),
+			1, "a", 1.5, "2.25", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), true)
+		db.MustExecContext(ctx, "INSERT INTO measure (id, label, reading, price, active) VALUES (2, 'b', 2, 3, false)")
+
+		rows, err := db.QueryxContext(ctx, "SELECT * FROM measure ORDER BY id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var got []map[string]any
+		for rows.Next() {
+			m := map[string]any{}
+			if err := rows.NormalizedMapScan(m); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, m)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 rows, got %d", len(got))
+		}
+
+		first, second := got[0], got[1]
+		if v, ok := first["id"].(int64); !ok || v != 1 {
+			t.Errorf("expected id to be int64 1, got %#v", first["id"])
+		}
+		if v, ok := first["label"].(string); !ok || v != "a" {
+			t.Errorf("expected label to be string a, got %#v", first["label"])
+		}
+		if v, ok := first["reading"].(float64); !ok || v != 1.5 {
+			t.Errorf("expected reading to be float64 1.5, got %#v", first["reading"])
+		}
+		if v, ok := second["reading"].(float64); !ok || v != 2 {
+			t.Errorf("expected reading to be float64 2, got %#v", second["reading"])
+		}
+		if v, ok := first["price"].(string); !ok || v != "2.25" {
+			t.Errorf("expected price to be string 2.25, got %#v", first["price"])
+		}
+		if v, ok := first["taken_at"].(time.Time); !ok || !v.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("expected taken_at to be a time.Time, got %#v", first["taken_at"])
+		}
+		if second["taken_at"] != nil {
+			t.Errorf("expected NULL to normalize to nil, got %#v", second["taken_at"])
+		}
+		// mysql reports booleans as TINYINT
+		if db.DriverName() != "mysql" {
+			if v, ok := first["active"].(bool); !ok || !v {
+				t.Errorf("expected active to be bool true, got %#v", first["active"])
+			}
+		}
+
+		values, err := db.QueryRowxContext(ctx, "SELECT id, label FROM measure WHERE id = 2").NormalizedSliceScan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 2 || values[0] != int64(2) || values[1] != "b" {
+			t.Errorf("unexpected values: %#v", values)
+		}
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	for _, v := range []any{uint64(math.MaxUint64), "18446744073709551615", []byte("9223372036854775808")} {
+		if _, err := normalize(v, normalInt); err == nil || !strings.Contains(err.Error(), "overflows int64") {
+			t.Errorf("expected %#v to overflow, got %v", v, err)
+		}
+	}
+	if v, err := normalize(uint64(math.MaxInt64), normalInt); err != nil || v != int64(math.MaxInt64) {
+		t.Errorf("expected MaxInt64, got %#v %v", v, err)
+	}
+	if _, err := normalize("-1x", normalInt); err == nil || strings.Contains(err.Error(), "overflows") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+
+	for v, expect := range map[any]string{
+		"$1.00":                     "$1.00",
+		"12345678901234567890.0001": "12345678901234567890.0001",
+		int64(3):                    "3",
+		2.25:                        "2.25",
+	} {
+		if got, err := normalize(v, normalDecimal); err != nil || got != expect {
+			t.Errorf("expected %#v to normalize to %q, got %#v %v", v, expect, got, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package reflectx
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// ScanFunc converts src, a value read from the database, and stores it in dst.
+// src is nil when the column is NULL.
+type ScanFunc func(src any, dst reflect.Value) error
+
+// ValueFunc converts v into a value which can be passed to a database driver.
+type ValueFunc func(v reflect.Value) (driver.Value, error)
+
+// A Converter holds the functions used to scan into and bind values of a type
+// which does not implement sql.Scanner or driver.Valuer itself.  Either
+// function may be nil.
+type Converter struct {
+	Scan  ScanFunc
+	Value ValueFunc
+}
+
+// RegisterConverter registers the functions used to scan into and bind fields
+// of type t.  Users of the mapper may apply them to fields of type *t as well,
+// scanning NULL into a nil pointer and binding nil pointers as NULL.  Cached
+// scan plans are discarded.
+func (m *Mapper) RegisterConverter(t reflect.Type, scan ScanFunc, value ValueFunc) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.converters == nil {
+		m.converters = make(map[reflect.Type]Converter)
+	}
+	m.converters[t] = Converter{Scan: scan, Value: value}
+	// plans may have been compiled without the converter
+	m.plans, m.planOrder = nil, nil
+}
+
+// Converter returns the converter registered for t.
+func (m *Mapper) Converter(t reflect.Type) (Converter, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	c, ok := m.converters[t]
+	return c, ok
+}
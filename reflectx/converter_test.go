@@ -0,0 +1,49 @@
+package reflectx
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConverter(t *testing.T) {
+	type Upper string
+
+	m := NewMapperFunc("db", strings.ToLower)
+	upper := reflect.TypeFor[Upper]()
+
+	if _, ok := m.Converter(upper); ok {
+		t.Fatal("expected no converter before registration")
+	}
+
+	m.RegisterConverter(upper, func(src any, dst reflect.Value) error {
+		dst.SetString(strings.ToUpper(src.(string)))
+		return nil
+	}, func(v reflect.Value) (driver.Value, error) {
+		return strings.ToLower(v.String()), nil
+	})
+
+	c, ok := m.Converter(upper)
+	if !ok {
+		t.Fatal("expected a converter after registration")
+	}
+	if _, ok := m.Converter(reflect.PointerTo(upper)); ok {
+		t.Error("expected no converter for the pointer type")
+	}
+
+	var u Upper
+	if err := c.Scan("abc", reflect.ValueOf(&u).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if u != "ABC" {
+		t.Errorf("expected ABC, got %s", u)
+	}
+	v, err := c.Value(reflect.ValueOf(u))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "abc" {
+		t.Errorf("expected abc, got %v", v)
+	}
+}
@@ -0,0 +1,47 @@
+package reflectx
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// A Discriminator selects the concrete type of values scanned into an
+// interface type from the value of a column.  Types maps each value of the
+// column, in its text form, to a struct type or a pointer to a struct type
+// implementing the interface.
+type Discriminator struct {
+	Column string
+	Types  map[string]reflect.Type
+}
+
+// RegisterDiscriminator registers the column and types used to scan rows into
+// the interface type iface.  Panics if iface is not an interface type, or if a
+// type is not a struct or pointer to struct implementing iface.
+func (m *Mapper) RegisterDiscriminator(iface reflect.Type, column string, types map[string]reflect.Type) {
+	mustBe(iface, reflect.Interface)
+	for value, t := range types {
+		if Deref(t).Kind() != reflect.Struct {
+			panic(fmt.Sprintf("reflectx: type %s for %s %q is not a struct", t, column, value))
+		}
+		if !t.Implements(iface) {
+			panic(fmt.Sprintf("reflectx: type %s for %s %q does not implement %s", t, column, value, iface))
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.discriminators == nil {
+		m.discriminators = make(map[reflect.Type]Discriminator)
+	}
+	// the caller's map is copied, so that changing it later has no effect
+	m.discriminators[iface] = Discriminator{Column: column, Types: maps.Clone(types)}
+}
+
+// Discriminator returns the discriminator registered for the interface type iface.
+func (m *Mapper) Discriminator(iface reflect.Type) (Discriminator, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	d, ok := m.discriminators[iface]
+	return d, ok
+}
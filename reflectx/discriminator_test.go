@@ -0,0 +1,43 @@
+package reflectx
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type shape interface{ Area() int }
+
+type square struct{ Side int }
+
+func (s square) Area() int { return s.Side * s.Side }
+
+func TestDiscriminator(t *testing.T) {
+	m := NewMapper("db")
+	iface := reflect.TypeFor[shape]()
+
+	if _, ok := m.Discriminator(iface); ok {
+		t.Fatal("expected no discriminator before registration")
+	}
+	types := map[string]reflect.Type{"square": reflect.TypeFor[square]()}
+	m.RegisterDiscriminator(iface, "kind", types)
+	delete(types, "square")
+	d, ok := m.Discriminator(iface)
+	if !ok || d.Column != "kind" || d.Types["square"] != reflect.TypeFor[square]() {
+		t.Errorf("unexpected discriminator: %#v", d)
+	}
+
+	for _, types := range []map[string]reflect.Type{
+		{"int": reflect.TypeFor[int]()},
+		{"stringer": reflect.TypeFor[struct{ fmt.Stringer }]()},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for %v", types)
+				}
+			}()
+			m.RegisterDiscriminator(iface, "kind", types)
+		}()
+	}
+}
@@ -21,9 +21,13 @@ func FieldByIndexes(v reflect.Value, indexes []int) reflect.Value {
 
 // FieldByIndexesReadOnly returns a value for a particular struct traversal,
 // but is not concerned with allocating nil pointers because the value is
-// going to be used for reading and not setting.
+// going to be used for reading and not setting.  If the traversal goes
+// through a nil pointer, the zero Value is returned.
 func FieldByIndexesReadOnly(v reflect.Value, indexes []int) reflect.Value {
 	for _, i := range indexes {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return reflect.Value{}
+		}
 		v = reflect.Indirect(v).Field(i)
 	}
 	return v
@@ -65,4 +65,8 @@ func TestFieldByIndexes(t *testing.T) {
 			checkResults(FieldByIndexesReadOnly(reflect.ValueOf(tc.value), tc.indexes))
 		}
 	}
+
+	// reading through a nil pointer gives the zero Value rather than panicking
+	v := FieldByIndexesReadOnly(reflect.ValueOf(A{}), []int{1, 1, 1})
+	assert.False(t, v.IsValid())
 }
@@ -1,6 +1,7 @@
 package reflectx
 
 import (
+	"container/list"
 	"reflect"
 	"sync"
 )
@@ -13,7 +14,14 @@ type Mapper struct {
 	tagName    string
 	tagMapFunc func(string) string
 	mapFunc    func(string) string
-	mutex      sync.Mutex
+	converters map[reflect.Type]Converter
+	// plans is a least recently used cache, ordered by planOrder
+	plans     map[planKey]*list.Element
+	planOrder *list.List
+	// discriminators is keyed by interface type
+	discriminators map[reflect.Type]Discriminator
+
+	mutex sync.Mutex
 }
 
 // TypeMap returns a mapping of field strings to int slices representing
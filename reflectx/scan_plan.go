@@ -0,0 +1,213 @@
+package reflectx
+
+import (
+	"container/list"
+	"hash/maphash"
+	"reflect"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// A ScanPlan is the compiled mapping of a list of columns onto the fields of a
+// struct type.  Plans are cached by the Mapper, so that repeated queries which
+// return the same columns skip name resolution, and hold the offset of each
+// field so that rows can be scanned without walking the struct.  A ScanPlan is
+// safe for concurrent use and must not be modified.
+type ScanPlan struct {
+	Type    reflect.Type
+	Map     *StructMap
+	Columns []string
+	// Traversals holds the traversal to the field of each column, or an
+	// empty traversal for columns which map to no field.
+	Traversals [][]int
+	// Offsets holds the byte offset of the field of each column from the
+	// start of the struct, or -1 if the field is a map, is reached through a
+	// pointer or the column maps to no field.
+	Offsets []int
+	// Types holds the type of the field of each column, or nil for columns
+	// which map to no field.
+	Types []reflect.Type
+
+	mutex    sync.Mutex
+	compiled map[any]any
+}
+
+// ScanPlanCacheSize is the number of scan plans a Mapper keeps.  Once it is
+// reached, the least recently used plan is dropped for each new one, so that
+// queries returning ever changing columns don't grow the cache without bound.
+const ScanPlanCacheSize = 1000
+
+// planKey identifies a cached ScanPlan.  Columns are hashed rather than
+// joined so that looking up a plan does not allocate; plans found under a key
+// are checked against the columns in case of collisions.
+type planKey struct {
+	t       reflect.Type
+	columns uint64
+}
+
+var planSeed = maphash.MakeSeed()
+
+// hashColumns returns the hash of columns used in a planKey.
+func hashColumns(columns []string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(planSeed)
+	for _, c := range columns {
+		h.WriteString(c)
+		h.WriteByte(0)
+	}
+	return h.Sum64()
+}
+
+// ScanPlan returns the plan for scanning columns into t, compiling it on
+// first use.  Panics if t is not a struct or Indirectable to a struct.
+func (m *Mapper) ScanPlan(t reflect.Type, columns []string) *ScanPlan {
+	t = Deref(t)
+	key := planKey{t: t, columns: hashColumns(columns)}
+
+	if p, ok := m.cachedPlan(key, columns); ok {
+		return p
+	}
+
+	p := newScanPlan(t, m.TypeMap(t), columns)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// another goroutine may have compiled the same plan meanwhile
+	if el, ok := m.plans[key]; ok {
+		m.planOrder.MoveToFront(el)
+		if cached := el.Value.(*ScanPlan); slices.Equal(cached.Columns, columns) {
+			return cached
+		}
+		// a colliding plan is replaced
+		el.Value = p
+		return p
+	}
+	if m.plans == nil {
+		m.plans = make(map[planKey]*list.Element)
+		m.planOrder = list.New()
+	}
+	m.plans[key] = m.planOrder.PushFront(p)
+	for m.planOrder.Len() > ScanPlanCacheSize {
+		el := m.planOrder.Back()
+		m.planOrder.Remove(el)
+		delete(m.plans, el.Value.(*ScanPlan).key())
+	}
+	return p
+}
+
+// cachedPlan returns the plan for columns cached under key, marking it as
+// recently used.
+func (m *Mapper) cachedPlan(key planKey, columns []string) (*ScanPlan, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	el, ok := m.plans[key]
+	if !ok {
+		return nil, false
+	}
+	p := el.Value.(*ScanPlan)
+	if !slices.Equal(p.Columns, columns) {
+		return nil, false
+	}
+	m.planOrder.MoveToFront(el)
+	return p, true
+}
+
+// key returns the key the plan is cached under.
+func (p *ScanPlan) key() planKey {
+	return planKey{t: p.Type, columns: hashColumns(p.Columns)}
+}
+
+func newScanPlan(t reflect.Type, tm *StructMap, columns []string) *ScanPlan {
+	mustBe(t, reflect.Struct)
+	p := &ScanPlan{
+		Type:       t,
+		Map:        tm,
+		Columns:    columns,
+		Traversals: make([][]int, len(columns)),
+		Offsets:    make([]int, len(columns)),
+		Types:      make([]reflect.Type, len(columns)),
+	}
+	for i, name := range columns {
+		p.Offsets[i] = -1
+		fi, ok := tm.Names[name]
+		if !ok {
+			p.Traversals[i] = []int{}
+			continue
+		}
+		p.Traversals[i] = fi.Index
+		p.Types[i] = fi.Field.Type
+		// FieldByIndexes makes nil maps, so map fields keep going through it
+		if fi.Field.Type.Kind() != reflect.Map {
+			p.Offsets[i] = fieldOffset(t, fi.Index)
+		}
+	}
+	return p
+}
+
+// fieldOffset returns the byte offset of the field at index from the start of
+// t, or -1 if the field is reached through a pointer.
+func fieldOffset(t reflect.Type, index []int) int {
+	var offset uintptr
+	for n, i := range index {
+		if n > 0 && t.Kind() != reflect.Struct {
+			return -1
+		}
+		f := t.Field(i)
+		offset += f.Offset
+		t = f.Type
+	}
+	return int(offset)
+}
+
+// FieldAddr returns a pointer to the field of column i in v, which must be an
+// addressable struct of the plan's type.  Fields reached through nil pointers
+// are allocated, as FieldByIndexes does.
+func (p *ScanPlan) FieldAddr(v reflect.Value, i int) any {
+	if p.Offsets[i] < 0 {
+		return FieldByIndexes(v, p.Traversals[i]).Addr().Interface()
+	}
+	return p.addr(v.Addr().UnsafePointer(), i)
+}
+
+// FieldAddrs stores in dest a pointer to the field of each column in v, which
+// must be an addressable struct of the plan's type.  Columns for which mask
+// is false, and columns which map to no field, are left untouched.
+func (p *ScanPlan) FieldAddrs(v reflect.Value, dest []any, mask []bool) {
+	base := v.Addr().UnsafePointer()
+	for i, offset := range p.Offsets {
+		switch {
+		case !mask[i] || len(p.Traversals[i]) == 0:
+		case offset < 0:
+			dest[i] = FieldByIndexes(v, p.Traversals[i]).Addr().Interface()
+		default:
+			dest[i] = p.addr(base, i)
+		}
+	}
+}
+
+// addr returns the pointer to the field of column i in the struct at base, as
+// an interface holding a pointer of the field's type.
+func (p *ScanPlan) addr(base unsafe.Pointer, i int) any {
+	return reflect.NewAt(p.Types[i], unsafe.Add(base, p.Offsets[i])).Interface()
+}
+
+// Compiled returns the value stored in the plan under key, calling compile
+// to produce it on first use.  It lets users of the mapper cache their own
+// data derived from the plan.  Errors returned by compile are not cached.
+func (p *ScanPlan) Compiled(key any, compile func() (any, error)) (any, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if v, ok := p.compiled[key]; ok {
+		return v, nil
+	}
+	v, err := compile()
+	if err != nil {
+		return nil, err
+	}
+	if p.compiled == nil {
+		p.compiled = make(map[any]any)
+	}
+	p.compiled[key] = v
+	return v, nil
+}
@@ -0,0 +1,121 @@
+package reflectx
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScanPlan(t *testing.T) {
+	type Inner struct {
+		Z int `db:"z"`
+	}
+	type Outer struct {
+		A     string            `db:"a"`
+		In    Inner             `db:"in"`
+		Ptr   *Inner            `db:"ptr"`
+		Props map[string]string `db:"props"`
+	}
+
+	m := NewMapperFunc("db", strings.ToLower)
+	columns := []string{"a", "in.z", "ptr.z", "props", "nope"}
+	p := m.ScanPlan(reflect.TypeFor[*Outer](), columns)
+
+	if p != m.ScanPlan(reflect.TypeFor[Outer](), []string{"a", "in.z", "ptr.z", "props", "nope"}) {
+		t.Error("expected the plan to be cached")
+	}
+	if p == m.ScanPlan(reflect.TypeFor[Outer](), []string{"a"}) {
+		t.Error("expected a different plan for different columns")
+	}
+
+	if p.Offsets[0] < 0 || p.Offsets[1] < 0 {
+		t.Errorf("expected offsets for direct fields, got %v", p.Offsets)
+	}
+	if p.Offsets[2] != -1 || p.Offsets[3] != -1 || p.Offsets[4] != -1 {
+		t.Errorf("expected no offset for pointer paths, maps and unknown columns, got %v", p.Offsets)
+	}
+	if len(p.Traversals[4]) != 0 || p.Types[4] != nil {
+		t.Errorf("expected no field for an unknown column")
+	}
+
+	var o Outer
+	v := reflect.ValueOf(&o).Elem()
+	*p.FieldAddr(v, 0).(*string) = "x"
+	*p.FieldAddr(v, 1).(*int) = 1
+	*p.FieldAddr(v, 2).(*int) = 2
+	p.FieldAddr(v, 3)
+	if o.A != "x" || o.In.Z != 1 || o.Ptr == nil || o.Ptr.Z != 2 || o.Props == nil {
+		t.Errorf("unexpected value: %#v", o)
+	}
+
+	calls := 0
+	compile := func() (any, error) {
+		calls++
+		return calls, nil
+	}
+	first, _ := p.Compiled("key", compile)
+	second, _ := p.Compiled("key", compile)
+	if first != 1 || second != 1 {
+		t.Errorf("expected the compiled value to be cached, got %v and %v", first, second)
+	}
+	if _, err := p.Compiled("bad", func() (any, error) { return nil, errors.New("bad") }); err == nil {
+		t.Error("expected the compile error")
+	}
+	if v, err := p.Compiled("bad", compile); err != nil || v != 2 {
+		t.Errorf("expected errors not to be cached, got %v, %v", v, err)
+	}
+
+	m.RegisterConverter(reflect.TypeFor[Inner](), nil, nil)
+	if p == m.ScanPlan(reflect.TypeFor[Outer](), columns) {
+		t.Error("expected registering a converter to discard plans")
+	}
+}
+
+func TestScanPlanCacheBound(t *testing.T) {
+	type T struct {
+		A int
+	}
+	m := NewMapper("")
+	first := m.ScanPlan(reflect.TypeFor[T](), []string{"A", "c0"})
+	for i := 1; i <= ScanPlanCacheSize; i++ {
+		m.ScanPlan(reflect.TypeFor[T](), []string{"A", "c" + strconv.Itoa(i)})
+	}
+	if len(m.plans) != ScanPlanCacheSize || m.planOrder.Len() != ScanPlanCacheSize {
+		t.Errorf("expected %d cached plans, got %d", ScanPlanCacheSize, len(m.plans))
+	}
+	if first == m.ScanPlan(reflect.TypeFor[T](), []string{"A", "c0"}) {
+		t.Error("expected the least recently used plan to be dropped")
+	}
+}
+
+// BenchmarkScanPlanL4 and BenchmarkTraversalsByNameL4 resolve a column and
+// address its field, as a query does with and without scan plans.
+func BenchmarkScanPlanL4(b *testing.B) {
+	m := NewMapper("")
+	e4 := E4{}
+	v := reflect.ValueOf(&e4).Elem()
+	names := []string{"A", "B", "C", "D"}
+
+	for b.Loop() {
+		p := m.ScanPlan(v.Type(), names)
+		for i := range names {
+			*p.FieldAddr(v, i).(*int) = i
+		}
+	}
+}
+
+func BenchmarkTraversalsByNameL4(b *testing.B) {
+	m := NewMapper("")
+	e4 := E4{}
+	v := reflect.ValueOf(&e4).Elem()
+	names := []string{"A", "B", "C", "D"}
+
+	for b.Loop() {
+		traversals := m.TraversalsByName(v.Type(), names)
+		for i, t := range traversals {
+			*FieldByIndexes(v, t).Addr().Interface().(*int) = i
+		}
+	}
+}
@@ -12,10 +12,12 @@ import (
 // Row is a reimplementation of sql.Row in order to gain access to the underlying
 // sql.Rows.Columns() data, necessary for StructScan.
 type Row struct {
-	err    error
-	unsafe bool
-	rows   *sql.Rows
-	Mapper *reflectx.Mapper
+	err      error
+	unsafe   bool
+	strict   bool
+	nullZero bool
+	rows     *sql.Rows
+	Mapper   *reflectx.Mapper
 }
 
 // Scan is a fixed implementation of sql.Row.Scan, which does not discard the
@@ -89,6 +91,9 @@ func (r *Row) scanAny(dest any, structOnly bool) error {
 		r.err = sql.ErrNoRows
 		return r.err
 	}
+	if dests, ok := dest.(Destinations); ok {
+		return r.scanDests(dests)
+	}
 	defer r.rows.Close()
 
 	v := reflect.ValueOf(dest)
@@ -121,19 +126,30 @@ func (r *Row) scanAny(dest any, structOnly bool) error {
 
 	m := r.Mapper
 
-	fields := m.TraversalsByName(v.Type(), columns)
+	s, err := newStructScanner(m, v.Type(), columns, r.nullZero)
+	if err != nil {
+		return err
+	}
 	// if we are not unsafe and are missing fields, return an error
-	if f, err := missingFields(fields); err != nil && !r.unsafe {
+	if f, err := missingFields(s.fields); err != nil && !r.unsafe {
 		return fmt.Errorf("missing destination name %s in %T", columns[f], dest)
 	}
+	if r.strict {
+		if err := s.checkFilled(dest); err != nil {
+			return err
+		}
+	}
 	values := make([]any, len(columns))
 
-	err = fieldsByTraversal(v, fields, values, true)
+	err = s.prepare(v, values)
 	if err != nil {
 		return err
 	}
 	// scan into the struct field pointers and append to our results
-	return r.Scan(values...)
+	if err := r.Scan(values...); err != nil {
+		return err
+	}
+	return s.finish(v)
 }
 
 // StructScan a single Row into dest.
@@ -13,12 +13,16 @@ import (
 // during a looped StructScan
 type Rows struct {
 	*sql.Rows
-	unsafe bool
-	Mapper *reflectx.Mapper
+	unsafe   bool
+	strict   bool
+	nullZero bool
+	Mapper   *reflectx.Mapper
 	// these fields cache memory use for a rows during iteration w/ structScan
 	started bool
 	fields  [][]int
 	values  []any
+	scanner *structScanner
+	poly    *polyScanner
 }
 
 // SliceScan using this Rows.
@@ -31,6 +35,26 @@ func (r *Rows) MapScan(dest map[string]any) error {
 	return MapScan(r, dest)
 }
 
+// NullTolerant makes StructScan on this Rows scan NULL into the zero value of
+// struct fields which cannot hold NULL instead of failing.  It returns r, and
+// must be called before the first StructScan.
+func (r *Rows) NullTolerant() *Rows {
+	r.nullZero = true
+	return r
+}
+
+// NextResultSet prepares the next result set for reading, as
+// sql.Rows.NextResultSet does.  The column mapping cached by StructScan is
+// reset, so that each set can be scanned into a different struct type.
+func (r *Rows) NextResultSet() bool {
+	r.started = false
+	r.fields = nil
+	r.values = nil
+	r.scanner = nil
+	r.poly = nil
+	return r.Rows.NextResultSet()
+}
+
 // ErrMustPassAPointerToStructScan is returned by StructScan when a non-pointer
 var ErrMustPassAPointerToStructScan = errors.New("must pass a pointer, not a value, to StructScan destination")
 
@@ -39,6 +63,9 @@ var ErrMustPassAPointerToStructScan = errors.New("must pass a pointer, not a val
 // prohibitive.  *Rows.StructScan caches the reflect work of matching up column
 // positions to fields to avoid that overhead per scan, which means it is not safe
 // to run StructScan on the same Rows instance with different struct types.
+// dest may also point to an interface for which a discriminator is registered
+// with the mapper, in which case each row gets a value of the type selected by
+// the discriminator column.
 func (r *Rows) StructScan(dest any) error {
 	v := reflect.ValueOf(dest)
 
@@ -48,6 +75,10 @@ func (r *Rows) StructScan(dest any) error {
 
 	v = v.Elem()
 
+	if v.Kind() == reflect.Interface {
+		return r.polyScan(v)
+	}
+
 	if !r.started {
 		columns, err := r.Columns()
 		if err != nil {
@@ -55,20 +86,53 @@ func (r *Rows) StructScan(dest any) error {
 		}
 		m := r.Mapper
 
-		r.fields = m.TraversalsByName(v.Type(), columns)
+		r.scanner, err = newStructScanner(m, v.Type(), columns, r.nullZero)
+		if err != nil {
+			return err
+		}
+		r.fields = r.scanner.fields
 		if f, err := missingFields(r.fields); err != nil && !r.unsafe {
 			return fmt.Errorf("missing destination name %s in %T", columns[f], dest)
 		}
+		if r.strict {
+			if err := r.scanner.checkFilled(dest); err != nil {
+				return err
+			}
+		}
 		r.values = make([]any, len(columns))
 		r.started = true
 	}
 
-	if err := fieldsByTraversal(v, r.fields, r.values, true); err != nil {
+	if err := r.scanner.prepare(v, r.values); err != nil {
 		return err
 	}
 
 	if err := r.Scan(r.values...); err != nil {
 		return err
 	}
+	if err := r.scanner.finish(v); err != nil {
+		return err
+	}
+	return r.Err()
+}
+
+// polyScan scans the current row into v, an interface with a registered
+// discriminator.
+func (r *Rows) polyScan(v reflect.Value) error {
+	if r.poly == nil {
+		p, err := newPolyScanner(r, r.Mapper, v.Type())
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("no discriminator registered for %s", v.Type())
+		}
+		r.poly = p
+	}
+	value, err := r.poly.scan(r)
+	if err != nil {
+		return err
+	}
+	v.Set(value)
 	return r.Err()
 }
@@ -0,0 +1,399 @@
+package sqlx
+
+import (
+	"database/sql"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+// A structScanner binds the columns of a result set to the fields of a struct
+// type.  It is built once per result set and reused for every row, so that the
+// name resolution done by the mapper is not repeated.
+type structScanner struct {
+	*scanPlan
+	holders []reflect.Value
+	// discard receives the columns which map to no field.
+	discard any
+}
+
+// A scanPlan is the part of a structScanner which only depends on the struct
+// type, the columns and the scan mode.  It is compiled once and cached on the
+// mapper's ScanPlan, so that it is shared by every query returning the same
+// columns.
+type scanPlan struct {
+	plan    *reflectx.ScanPlan
+	tm      *reflectx.StructMap
+	columns []string
+	fields  [][]int
+	// direct marks the columns scanned straight into their field; the
+	// others are scanned through a holder of the type in holderTypes.
+	direct      []bool
+	holderTypes []reflect.Type
+	// held is set when any column is scanned through a holder.
+	held bool
+	// converters holds the registered converter of columns whose field type
+	// has one; such columns are scanned into an any holder.
+	converters []converter
+	// json marks the columns whose field has the `json` option; such columns
+	// are scanned into a []byte holder and decoded.
+	json []bool
+	// defaults holds the value of the `default` option of each column's field,
+	// used when the column is NULL.
+	defaults []fieldDefault
+	groups   []nullableGroup
+	// memberOf lists, for each column, the nullable groups it belongs to.
+	memberOf [][]int
+}
+
+// A scanMode is the key of a scanPlan in the compiled data of a ScanPlan.
+type scanMode struct {
+	nullZero bool
+}
+
+// A nullableGroup is a pointer field tagged with the `nullable` option, along
+// with the columns which fill it.  The pointer is left nil when all of those
+// columns are NULL.
+type nullableGroup struct {
+	index   []int
+	columns []int
+}
+
+// A fieldDefault is the value of a field's `default` option, as text and as a
+// value of the field's type.
+type fieldDefault struct {
+	text  string
+	value reflect.Value
+}
+
+// set stores the default in f, copying it first if it is a pointer so that
+// rows do not share it.
+func (d fieldDefault) set(f reflect.Value) {
+	if d.value.Kind() != reflect.Pointer {
+		f.Set(d.value)
+		return
+	}
+	p := reflect.New(d.value.Type().Elem())
+	p.Elem().Set(d.value.Elem())
+	f.Set(p)
+}
+
+// newStructScanner returns a structScanner for scanning columns into t.  If
+// nullZero is set, fields which cannot hold NULL are scanned through holders
+// so that NULL leaves them at their zero value.
+func newStructScanner(m *reflectx.Mapper, t reflect.Type, columns []string, nullZero bool) (*structScanner, error) {
+	plan := m.ScanPlan(reflectx.Deref(t), columns)
+	compiled, err := plan.Compiled(scanMode{nullZero: nullZero}, func() (any, error) {
+		return compileScanPlan(m, plan, nullZero)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p := compiled.(*scanPlan)
+	s := &structScanner{scanPlan: p}
+	if !p.held {
+		return s, nil
+	}
+	s.holders = make([]reflect.Value, len(p.columns))
+	for i, t := range p.holderTypes {
+		if t != nil {
+			s.holders[i] = reflect.New(t)
+		}
+	}
+	return s, nil
+}
+
+// compileScanPlan works out how each column of plan is scanned.
+func compileScanPlan(m *reflectx.Mapper, plan *reflectx.ScanPlan, nullZero bool) (*scanPlan, error) {
+	columns := plan.Columns
+	tm := plan.Map
+	p := &scanPlan{
+		plan:        plan,
+		tm:          tm,
+		columns:     columns,
+		fields:      plan.Traversals,
+		direct:      make([]bool, len(columns)),
+		holderTypes: make([]reflect.Type, len(columns)),
+		converters:  make([]converter, len(columns)),
+		json:        make([]bool, len(columns)),
+		defaults:    make([]fieldDefault, len(columns)),
+		memberOf:    make([][]int, len(columns)),
+	}
+
+	for i, traversal := range p.fields {
+		if len(traversal) == 0 {
+			continue
+		}
+		p.direct[i] = true
+		fi := tm.GetByTraversal(traversal)
+		ft := fi.Field.Type
+		if c, ok := converterFor(m, ft); ok && c.Scan != nil {
+			p.converters[i] = c
+			p.hold(i, reflect.TypeFor[any]())
+		} else if _, ok := fi.Options["json"]; ok {
+			p.json[i] = true
+			p.hold(i, reflect.TypeFor[[]byte]())
+		}
+		if text, ok := fi.Options["default"]; ok {
+			p.defaults[i].text = text
+			if p.holderTypes[i] == nil {
+				v, err := parseDefault(ft, text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid default for destination name %s: %w", columns[i], err)
+				}
+				p.defaults[i].value = v
+				p.hold(i, reflect.PointerTo(ft))
+			}
+		} else if nullZero && !acceptsNull(ft) {
+			p.hold(i, reflect.PointerTo(ft))
+		}
+		for n := 1; n < len(traversal); n++ {
+			fi := tm.GetByTraversal(traversal[:n])
+			if fi == nil || fi.Field.Type.Kind() != reflect.Pointer {
+				continue
+			}
+			if _, ok := fi.Options["nullable"]; !ok {
+				continue
+			}
+			p.hold(i, reflect.PointerTo(ft))
+			p.memberOf[i] = append(p.memberOf[i], p.group(traversal[:n], i))
+		}
+	}
+	return p, nil
+}
+
+// acceptsNull reports whether database/sql can scan NULL into a value of type t.
+func acceptsNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(_scannerInterface)
+}
+
+// parseDefault parses text into a value of type t.  Pointer types get a pointer
+// to the parsed value.  Besides the basic kinds, types implementing sql.Scanner
+// or encoding.TextUnmarshaler are supported.
+func parseDefault(t reflect.Type, text string) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		v, err := parseDefault(t.Elem(), text)
+		if err != nil {
+			return v, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, nil
+	}
+
+	v := reflect.New(t).Elem()
+	switch p := v.Addr().Interface().(type) {
+	case sql.Scanner:
+		return v, p.Scan(text)
+	case encoding.TextUnmarshaler:
+		return v, p.UnmarshalText([]byte(text))
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	default:
+		return v, fmt.Errorf("unsupported type %s", t)
+	}
+	return v, nil
+}
+
+// hold marks column i to be scanned through a holder of type t, which must be
+// a pointer or interface type so that NULL can be told apart.
+func (p *scanPlan) hold(i int, t reflect.Type) {
+	if p.holderTypes[i] != nil {
+		return
+	}
+	p.direct[i] = false
+	p.holderTypes[i] = t
+	p.held = true
+}
+
+// group adds column i to the nullable group for the field at index, returning
+// the position of the group.
+func (p *scanPlan) group(index []int, i int) int {
+	for g := range p.groups {
+		if slices.Equal(p.groups[g].index, index) {
+			p.groups[g].columns = append(p.groups[g].columns, i)
+			return g
+		}
+	}
+	p.groups = append(p.groups, nullableGroup{index: index, columns: []int{i}})
+	return len(p.groups) - 1
+}
+
+// prepare fills values with the scan destinations for v.
+func (s *structScanner) prepare(v reflect.Value, values []any) error {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return errors.New("argument not a struct")
+	}
+	s.plan.FieldAddrs(v, values, s.direct)
+	for i, direct := range s.direct {
+		switch {
+		case direct:
+		case s.holderTypes[i] != nil:
+			values[i] = s.holders[i].Interface()
+		default:
+			values[i] = &s.discard
+		}
+	}
+	return nil
+}
+
+// finish copies the values scanned into holders to their fields in v.
+func (s *structScanner) finish(v reflect.Value) error {
+	if !s.held {
+		return nil
+	}
+	v = reflect.Indirect(v)
+
+	null := make([]bool, len(s.groups))
+	for g, group := range s.groups {
+		null[g] = true
+		for _, i := range group.columns {
+			if !s.holders[i].Elem().IsNil() {
+				null[g] = false
+				break
+			}
+		}
+	}
+
+	// groups were added in traversal order, so outer groups come first and
+	// an inner group is skipped when its enclosing group is already nil.
+	cleared := make([]bool, len(s.groups))
+	for g, group := range s.groups {
+		if !null[g] || s.insideCleared(g, cleared) {
+			continue
+		}
+		n := len(group.index)
+		parent := reflect.Indirect(reflectx.FieldByIndexes(v, group.index[:n-1]))
+		parent.Field(group.index[n-1]).SetZero()
+		cleared[g] = true
+	}
+
+	for i, h := range s.holders {
+		if !h.IsValid() || slices.ContainsFunc(s.memberOf[i], func(g int) bool { return null[g] }) {
+			continue
+		}
+		f := reflectx.FieldByIndexes(v, s.fields[i])
+		switch {
+		case s.converters[i].Scan != nil:
+			src := h.Elem().Interface()
+			if src == nil && s.defaults[i].text != "" {
+				src = s.defaults[i].text
+			}
+			if err := s.converters[i].scan(src, f); err != nil {
+				return fmt.Errorf("sql: Scan error on column index %d, name %q: %w", i, s.columns[i], err)
+			}
+		case s.json[i]:
+			data := h.Elem().Bytes()
+			if data == nil && s.defaults[i].text != "" {
+				data = []byte(s.defaults[i].text)
+			}
+			if err := decodeJSON(data, f); err != nil {
+				return fmt.Errorf("sql: Scan error on column index %d, name %q: %w", i, s.columns[i], err)
+			}
+		case h.Elem().IsNil() && s.defaults[i].value.IsValid():
+			s.defaults[i].set(f)
+		case h.Elem().IsNil():
+			f.SetZero()
+		default:
+			f.Set(h.Elem().Elem())
+		}
+	}
+	return nil
+}
+
+// insideCleared reports whether group g is nested in a group which has been
+// cleared.
+func (s *structScanner) insideCleared(g int, cleared []bool) bool {
+	index := s.groups[g].index
+	for o, group := range s.groups {
+		if cleared[o] && len(group.index) < len(index) && slices.Equal(group.index, index[:len(group.index)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFilled returns an error naming every field of the struct which is not
+// filled by a column, unless it or one of its parents is tagged with the
+// `optional` option.  A field counts as filled when a column scans into it,
+// into one of its parents or into one of its children.  When none of a
+// field's children are filled, only the field itself is named.
+func (s *structScanner) checkFilled(dest any) error {
+	var unfilled []string
+	reported := map[*reflectx.FieldInfo]bool{}
+	for _, fi := range s.tm.Index {
+		if fi.Embedded || isOptional(fi) || hasReportedParent(fi, reported) {
+			continue
+		}
+		filled := slices.ContainsFunc(s.fields, func(t []int) bool {
+			n := min(len(t), len(fi.Index))
+			return len(t) > 0 && slices.Equal(t[:n], fi.Index[:n])
+		})
+		if !filled {
+			reported[fi] = true
+			unfilled = append(unfilled, fi.Path)
+		}
+	}
+	if len(unfilled) > 0 {
+		return fmt.Errorf("no column for destination name %s in %T", strings.Join(unfilled, ", "), dest)
+	}
+	return nil
+}
+
+// hasReportedParent reports whether one of the parents of fi is in reported.
+func hasReportedParent(fi *reflectx.FieldInfo, reported map[*reflectx.FieldInfo]bool) bool {
+	for p := fi.Parent; p != nil; p = p.Parent {
+		if reported[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// isOptional reports whether fi or one of its parents has the `optional` option.
+func isOptional(fi *reflectx.FieldInfo) bool {
+	for ; fi != nil; fi = fi.Parent {
+		if _, ok := fi.Options["optional"]; ok {
+			return true
+		}
+	}
+	return false
+}
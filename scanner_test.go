@@ -0,0 +1,470 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/i9si-sistemas/sqlx/reflectx"
+)
+
+var addressSchema = Schema{
+	create: `
+CREATE TABLE customer (
+	id integer,
+	name text
+);
+
+CREATE TABLE address (
+	customer_id integer,
+	city text,
+	zip text NULL,
+	lat integer NULL,
+	lng integer NULL
+);
+`,
+	drop: `
+drop table customer;
+drop table address;
+`,
+}
+
+func loadAddressFixture(ctx context.Context, db *DB) {
+	for _, q := range []string{
+		"INSERT INTO customer (id, name) VALUES (1, 'ann'), (2, 'bob'), (3, 'cid')",
+		"INSERT INTO address (customer_id, city, zip, lat, lng) VALUES (1, 'Lisbon', '1000', 38, -9)",
+		"INSERT INTO address (customer_id, city) VALUES (3, 'Porto')",
+	} {
+		db.MustExecContext(ctx, q)
+	}
+}
+
+func TestNullableStructPointers(t *testing.T) {
+	RunWithSchemaContext(context.Background(), addressSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadAddressFixture(ctx, db)
+
+		type Geo struct {
+			Lat int `db:"lat"`
+			Lng int `db:"lng"`
+		}
+		type Address struct {
+			City string  `db:"city"`
+			Zip  *string `db:"zip"`
+			Geo  *Geo    `db:"geo,nullable"`
+		}
+		type Customer struct {
+			ID      int      `db:"id"`
+			Name    string   `db:"name"`
+			Address *Address `db:"address,nullable"`
+		}
+
+		query := quoteAliases(db, `
+			SELECT c.id, c.name, a.city AS "address.city", a.zip AS "address.zip",
+				a.lat AS "address.geo.lat", a.lng AS "address.geo.lng"
+			FROM customer c LEFT JOIN address a ON a.customer_id = c.id
+			ORDER BY c.id`)
+
+		var customers []Customer
+		if err := db.SelectContext(ctx, &customers, query); err != nil {
+			t.Fatal(err)
+		}
+		if len(customers) != 3 {
+			t.Fatalf("expected 3 customers, got %d", len(customers))
+		}
+		ann, bob, cid := customers[0], customers[1], customers[2]
+		if ann.Address == nil || ann.Address.City != "Lisbon" || ann.Address.Geo == nil || ann.Address.Geo.Lng != -9 {
+			t.Errorf("unexpected address for ann: %#v", ann.Address)
+		}
+		if bob.Address != nil {
+			t.Errorf("expected a nil address for bob, got %#v", bob.Address)
+		}
+		if cid.Address == nil || cid.Address.City != "Porto" || cid.Address.Zip != nil {
+			t.Errorf("unexpected address for cid: %#v", cid.Address)
+		}
+		if cid.Address != nil && cid.Address.Geo != nil {
+			t.Errorf("expected a nil geo for cid, got %#v", cid.Address.Geo)
+		}
+
+		// a reused destination must be reset when the next row is all NULL
+		rows, err := db.QueryxContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var c Customer
+		for rows.Next() {
+			if err := rows.StructScan(&c); err != nil {
+				t.Fatal(err)
+			}
+			if c.Name == "bob" && c.Address != nil {
+				t.Errorf("expected the address to be reset for bob, got %#v", c.Address)
+			}
+		}
+
+		var one Customer
+		err = db.GetContext(ctx, &one, db.Rebind(quoteAliases(db, `
+			SELECT c.id, c.name, a.city AS "address.city", a.zip AS "address.zip",
+				a.lat AS "address.geo.lat", a.lng AS "address.geo.lng"
+			FROM customer c LEFT JOIN address a ON a.customer_id = c.id
+			WHERE c.id = ?`)), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if one.Address != nil {
+			t.Errorf("expected a nil address, got %#v", one.Address)
+		}
+
+		// without the option, the pointer is always allocated
+		type EagerCustomer struct {
+			ID      int      `db:"id"`
+			Name    string   `db:"name"`
+			Address *Address `db:"address"`
+		}
+		var eager []EagerCustomer
+		err = db.SelectContext(ctx, &eager, quoteAliases(db, `
+			SELECT c.id, c.name, a.zip AS "address.zip"
+			FROM customer c LEFT JOIN address a ON a.customer_id = c.id
+			ORDER BY c.id`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eager[1].Address == nil {
+			t.Error("expected an allocated address without the nullable option")
+		}
+	})
+}
+
+func TestStrictScan(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		loadDefaultFixtureContext(ctx, db, t)
+
+		type Report struct {
+			FirstName string `db:"first_name"`
+			LastName  string `db:"last_name"`
+			Email     string
+			Notes     string `db:"notes,optional"`
+		}
+
+		strict := db.Strict()
+
+		var r Report
+		err := strict.GetContext(ctx, &r, "SELECT first_name, last_name, email FROM person LIMIT 1")
+		if err != nil {
+			t.Errorf("expected optional fields to be exempt, got %v", err)
+		}
+
+		var rs []Report
+		err = strict.SelectContext(ctx, &rs, "SELECT first_name FROM person")
+		if err == nil {
+			t.Fatal("expected an error for unfilled fields")
+		}
+		if msg := err.Error(); !strings.Contains(msg, "last_name, email") || strings.Contains(msg, "notes") {
+			t.Errorf("unexpected error message: %s", msg)
+		}
+
+		// nested structs count as filled when a column scans into them
+		var places []Place
+		if err = strict.SelectContext(ctx, &places, "SELECT * FROM place"); err != nil {
+			t.Error(err)
+		}
+
+		var pp []PersonPlace
+		err = strict.SelectContext(ctx, &pp, "SELECT first_name, last_name, email, added_at, country FROM person, place")
+		if err == nil || !strings.Contains(err.Error(), "city, telcode") {
+			t.Errorf("expected city and telcode to be reported, got %v", err)
+		}
+
+		// the mode is opt-in, and unsafe copies keep it
+		if err = db.SelectContext(ctx, &rs, "SELECT first_name FROM person"); err != nil {
+			t.Error(err)
+		}
+		if err = strict.Unsafe().SelectContext(ctx, &rs, "SELECT first_name, 1 AS extra FROM person"); err == nil {
+			t.Error("expected Unsafe to keep the strict mode")
+		}
+
+		stmt, err := strict.PreparexContext(ctx, "SELECT first_name FROM person")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		plain, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = plain.StmtxContext(ctx, stmt).SelectContext(ctx, &rs); err == nil {
+			t.Error("expected Stmtx to keep the strict mode of the statement")
+		}
+		plain.Rollback()
+
+		tx, err := strict.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		rows, err := tx.QueryxContext(ctx, "SELECT 'a' AS first_name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		if rows.Next() {
+			if err := rows.StructScan(&r); err == nil {
+				t.Error("expected strictness to be inherited by transactions")
+			}
+		}
+
+		// statements moved into a transaction keep the mode of the statement
+		// or of the transaction
+		if err = tx.Stmtx(stmt.Stmt).SelectContext(ctx, &rs); err == nil {
+			t.Error("expected Stmtx to take the strict mode of the transaction")
+		}
+		if ns := tx.NamedStmt(&NamedStmt{Stmt: &Stmt{Stmt: stmt.Stmt}}); !isStrict(ns) {
+			t.Error("expected NamedStmt to take the strict mode of the transaction")
+		}
+
+		// the flags are kept on value copies, as with isUnsafe
+		if !isStrict(*rows) || !isStrict(*strict) || !isNullTolerant(*db.NullTolerant()) {
+			t.Error("expected the flags of value copies to be reported")
+		}
+	})
+}
+
+func TestConverters(t *testing.T) {
+	var schema = Schema{
+		create: `
+CREATE TABLE hosts (
+	name text,
+	addr text,
+	backup text NULL
+);`,
+		drop: `drop table hosts;`,
+	}
+
+	RunWithSchemaContext(context.Background(), schema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		type Host struct {
+			Name   string      `db:"name"`
+			Addr   netip.Addr  `db:"addr"`
+			Backup *netip.Addr `db:"backup"`
+		}
+
+		db = NewDb(db.DB, db.DriverName())
+		db.Mapper = reflectx.NewMapperFunc("db", strings.ToLower)
+		db.Mapper.RegisterConverter(reflect.TypeFor[netip.Addr](), func(src any, dst reflect.Value) error {
+			var s string
+			switch src := src.(type) {
+			case string:
+				s = src
+			case []byte:
+				s = string(src)
+			default:
+				return fmt.Errorf("cannot scan %T into netip.Addr", src)
+			}
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(addr))
+			return nil
+		}, func(v reflect.Value) (driver.Value, error) {
+			return v.Interface().(netip.Addr).String(), nil
+		})
+
+		backup := netip.MustParseAddr("10.0.0.2")
+		hosts := []Host{
+			{Name: "a", Addr: netip.MustParseAddr("10.0.0.1"), Backup: &backup},
+			{Name: "b", Addr: netip.MustParseAddr("::1")},
+		}
+		for _, h := range hosts {
+			if _, err := db.NamedExecContext(ctx, "INSERT INTO hosts (name, addr, backup) VALUES (:name, :addr, :backup)", h); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var got []Host
+		if err := db.SelectContext(ctx, &got, "SELECT * FROM hosts ORDER BY name"); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 hosts, got %d", len(got))
+		}
+		if got[0].Addr != hosts[0].Addr || got[0].Backup == nil || *got[0].Backup != backup {
+			t.Errorf("unexpected host: %#v", got[0])
+		}
+		if got[1].Addr != hosts[1].Addr || got[1].Backup != nil {
+			t.Errorf("unexpected host: %#v", got[1])
+		}
+
+		var h Host
+		err := db.GetContext(ctx, &h, "SELECT name, 'not an address' AS addr, backup FROM hosts LIMIT 1")
+		if err == nil || !strings.Contains(err.Error(), `name "addr"`) {
+			t.Errorf("expected a conversion error naming the column, got %v", err)
+		}
+	})
+}
+
+func TestNullTolerant(t *testing.T) {
+	RunWithSchemaContext(context.Background(), defaultSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		db.MustExecContext(ctx, "INSERT INTO nullperson (first_name, last_name, email) VALUES ('ann', NULL, NULL)")
+		db.MustExecContext(ctx, "INSERT INTO nullperson (first_name, last_name, email) VALUES (NULL, 'doe', 'doe@example.com')")
+
+		type NullPerson struct {
+			FirstName string `db:"first_name"`
+			LastName  string `db:"last_name,default=unknown"`
+			Email     *string
+		}
+
+		var people []NullPerson
+		err := db.SelectContext(ctx, &people, "SELECT * FROM nullperson ORDER BY email")
+		if err == nil {
+			t.Error("expected NULL to fail to scan into a string without the mode")
+		}
+
+		err = db.NullTolerant().SelectContext(ctx, &people, "SELECT * FROM nullperson ORDER BY first_name DESC")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(people) != 2 {
+			t.Fatalf("expected 2 people, got %d", len(people))
+		}
+		ann, doe := people[0], people[1]
+		if ann.FirstName != "ann" || ann.LastName != "unknown" || ann.Email != nil {
+			t.Errorf("unexpected person: %#v", ann)
+		}
+		if doe.FirstName != "" || doe.LastName != "doe" || doe.Email == nil {
+			t.Errorf("unexpected person: %#v", doe)
+		}
+
+		// defaults apply without the mode
+		type Scored struct {
+			Name  string   `db:"first_name"`
+			Score int      `db:"score,default=42"`
+			Ratio *float64 `db:"ratio,default=0.5"`
+		}
+		var scored []Scored
+		err = db.SelectContext(ctx, &scored, "SELECT first_name, NULL AS score, NULL AS ratio FROM nullperson WHERE first_name IS NOT NULL")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scored) != 1 || scored[0].Score != 42 || scored[0].Ratio == nil || *scored[0].Ratio != 0.5 {
+			t.Errorf("unexpected scores: %#v", scored)
+		}
+
+		// statements moved into a transaction keep the mode of the statement
+		// or of the transaction
+		stmt, err := db.NullTolerant().PreparexContext(ctx, "SELECT * FROM nullperson")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tx.Stmtx(stmt).SelectContext(ctx, &people); err != nil {
+			t.Errorf("expected Stmtx to keep the NULL-tolerant mode, got %v", err)
+		}
+		tx.Rollback()
+		tx, err = db.NullTolerant().BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tx.NamedStmtContext(ctx, &NamedStmt{Stmt: &Stmt{Stmt: stmt.Stmt}}).SelectContext(ctx, &people, map[string]any{}); err != nil {
+			t.Errorf("expected NamedStmt to take the NULL-tolerant mode of the transaction, got %v", err)
+		}
+		tx.Rollback()
+
+		rows, err := db.QueryxContext(ctx, "SELECT * FROM nullperson")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		rows.NullTolerant()
+		for rows.Next() {
+			var p NullPerson
+			if err := rows.StructScan(&p); err != nil {
+				t.Error(err)
+			}
+		}
+
+		type Bad struct {
+			Score int `db:"score,default=high"`
+		}
+		var bad Bad
+		err = db.GetContext(ctx, &bad, "SELECT 1 AS score")
+		if err == nil || !strings.Contains(err.Error(), "invalid default") {
+			t.Errorf("expected an invalid default error, got %v", err)
+		}
+	})
+}
+
+func TestJSONFields(t *testing.T) {
+	RunWithSchemaContext(context.Background(), groupedSchema, t, func(ctx context.Context, db *DB, t *testing.T) {
+		type Item struct {
+			SKU string `json:"sku"`
+			Qty int    `json:"qty"`
+		}
+		type Order struct {
+			ID       int             `db:"id"`
+			Customer string          `db:"customer"`
+			Items    []Item          `db:"items,json"`
+			Meta     *map[string]int `db:"meta,json"`
+		}
+
+		orders := []Order{
+			{ID: 1, Customer: "ann", Items: []Item{{"a", 1}, {"b", 2}}},
+			{ID: 2, Customer: "bob"},
+		}
+		for _, o := range orders {
+			_, err := db.NamedExecContext(ctx, "INSERT INTO orders (id, customer) VALUES (:id, :customer)", o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range o.Items {
+				db.MustExecContext(ctx, db.Rebind("INSERT INTO order_items (order_id, sku, qty) VALUES (?, ?, ?)"), o.ID, item.SKU, item.Qty)
+			}
+		}
+
+		query, args, err := db.BindNamed("SELECT :items AS items, :meta AS meta", orders[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args[0] != `[{"sku":"a","qty":1},{"sku":"b","qty":2}]` || args[1] != nil {
+			t.Errorf("unexpected args for %s: %#v", query, args)
+		}
+		var echo Order
+		if err = db.GetContext(ctx, &echo, "SELECT 1 AS id, 'ann' AS customer, "+db.Rebind("?")+" AS items, '{\"n\":1}' AS meta", args[0]); err != nil {
+			t.Fatal(err)
+		}
+		if len(echo.Items) != 2 || echo.Items[1].Qty != 2 || echo.Meta == nil || (*echo.Meta)["n"] != 1 {
+			t.Errorf("unexpected order: %#v", echo)
+		}
+
+		if db.DriverName() != "sqlite3" {
+			return
+		}
+		var got []Order
+		err = db.SelectContext(ctx, &got, `
+			SELECT o.id, o.customer, NULL AS meta,
+				(SELECT json_group_array(json_object('sku', i.sku, 'qty', i.qty))
+				 FROM order_items i WHERE i.order_id = o.id) AS items
+			FROM orders o ORDER BY o.id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || len(got[0].Items) != 2 || got[0].Items[0].SKU != "a" || got[0].Meta != nil {
+			t.Errorf("unexpected orders: %#v", got)
+		}
+		if got[1].Items == nil || len(got[1].Items) != 0 {
+			t.Errorf("expected an empty list of items, got %#v", got[1].Items)
+		}
+
+		var bad Order
+		err = db.GetContext(ctx, &bad, "SELECT 1 AS id, 'x' AS customer, 'nope' AS items, NULL AS meta")
+		if err == nil || !strings.Contains(err.Error(), `name "items"`) {
+			t.Errorf("expected a decoding error naming the column, got %v", err)
+		}
+	})
+}
@@ -92,6 +92,10 @@ func isUnsafe(i any) bool {
 		return v.unsafe
 	case *Tx:
 		return v.unsafe
+	case Conn:
+		return v.unsafe
+	case *Conn:
+		return v.unsafe
 	case sql.Rows, *sql.Rows:
 		return false
 	default:
@@ -99,9 +103,91 @@ func isUnsafe(i any) bool {
 	}
 }
 
+// isStrict determines if any of our extensions are strict
+func isStrict(i any) bool {
+	switch v := i.(type) {
+	case Row:
+		return v.strict
+	case *Row:
+		return v.strict
+	case Rows:
+		return v.strict
+	case *Rows:
+		return v.strict
+	case NamedStmt:
+		return v.Stmt.strict
+	case *NamedStmt:
+		return v.Stmt.strict
+	case Stmt:
+		return v.strict
+	case *Stmt:
+		return v.strict
+	case qStmt:
+		return v.strict
+	case *qStmt:
+		return v.strict
+	case DB:
+		return v.strict
+	case *DB:
+		return v.strict
+	case Tx:
+		return v.strict
+	case *Tx:
+		return v.strict
+	case Conn:
+		return v.strict
+	case *Conn:
+		return v.strict
+	default:
+		return false
+	}
+}
+
+// isNullTolerant determines if any of our extensions scan NULL into zero values
+func isNullTolerant(i any) bool {
+	switch v := i.(type) {
+	case Row:
+		return v.nullZero
+	case *Row:
+		return v.nullZero
+	case Rows:
+		return v.nullZero
+	case *Rows:
+		return v.nullZero
+	case NamedStmt:
+		return v.Stmt.nullZero
+	case *NamedStmt:
+		return v.Stmt.nullZero
+	case Stmt:
+		return v.nullZero
+	case *Stmt:
+		return v.nullZero
+	case qStmt:
+		return v.nullZero
+	case *qStmt:
+		return v.nullZero
+	case DB:
+		return v.nullZero
+	case *DB:
+		return v.nullZero
+	case Tx:
+		return v.nullZero
+	case *Tx:
+		return v.nullZero
+	case Conn:
+		return v.nullZero
+	case *Conn:
+		return v.nullZero
+	default:
+		return false
+	}
+}
+
 var _scannerInterface = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 
-//lint:ignore U1000 ignoring this for now
+// _rowMapType is the type of the maps filled by MapScan.
+var _rowMapType = reflect.TypeOf(map[string]any(nil))
+
 var _valuerInterface = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 
 // Preparex prepares a statement.
@@ -110,12 +196,13 @@ func Preparex(p Preparer, query string) (*Stmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p)}, err
+	return &Stmt{Stmt: s, unsafe: isUnsafe(p), strict: isStrict(p), nullZero: isNullTolerant(p), Mapper: mapperFor(p)}, err
 }
 
 // Select executes a query using the provided Queryer, and StructScans each row
 // into dest, which must be a slice.  If the slice elements are scannable, then
-// the result set must have only one column.  Otherwise, StructScan is used.
+// the result set must have only one column.  Slices of map[string]any get one
+// map per row, as MapScan fills it.  Otherwise, StructScan is used.
 // The *sql.Rows are closed automatically.
 // Any placeholder parameters are replaced with supplied args.
 func Select(q Queryer, dest any, query string, args ...any) error {
@@ -128,9 +215,66 @@ func Select(q Queryer, dest any, query string, args ...any) error {
 	return scanAll(rows, dest, false)
 }
 
+// SelectEach executes a query using the provided Queryer, and scans each row
+// into dest, calling fn after every row.  Unlike Select, dest is a single value
+// which is reused for every row, so that results need not be held in memory.
+// If dest is scannable, the result set must have only one column.  Otherwise,
+// StructScan is used.  Iteration stops at the first error returned by fn,
+// which is returned.  The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectEach(q Queryer, dest any, fn func() error, query string, args ...any) error {
+	rows, err := q.Queryx(query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanEach(rows, dest, fn)
+}
+
+// scanEach scans every row of rows into dest and calls fn.  Structs go through
+// Rows.StructScan, which caches the column mapping for the whole loop.
+func scanEach(rows *Rows, dest any, fn func() error) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer {
+		return errors.New("must pass a pointer, not a value, to SelectEach destination")
+	}
+	if v.IsNil() {
+		return errors.New("nil pointer passed to SelectEach destination")
+	}
+	base := reflectx.Deref(v.Type())
+	scannable := isScannable(base)
+	if scannable {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		if len(columns) > 1 {
+			return fmt.Errorf("non-struct dest type %s with >1 columns (%d)", base.Kind(), len(columns))
+		}
+	}
+
+	for rows.Next() {
+		var err error
+		if scannable {
+			err = rows.Scan(dest)
+		} else {
+			err = rows.StructScan(dest)
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // Get does a QueryRow using the provided Queryer, and scans the resulting row
 // to dest.  If dest is scannable, the result must only have one column.  Otherwise,
-// StructScan is used.  Get will return sql.ErrNoRows like row.Scan would.
+// StructScan is used.  Several destinations can be given with Dests.  Get will
+// return sql.ErrNoRows like row.Scan would.
 // Any placeholder parameters are replaced with supplied args.
 // An error is returned if the result set is empty.
 func Get(q Queryer, dest any, query string, args ...any) error {
@@ -285,6 +429,25 @@ func scanAll(rows rowsi, dest any, structOnly bool) error {
 
 	isPtr := slice.Elem().Kind() == reflect.Pointer
 	base := reflectx.Deref(slice.Elem())
+
+	// interfaces with a discriminator get a concrete type per row
+	if base.Kind() == reflect.Interface && !isPtr {
+		p, err := newPolyScanner(rows, rowsMapper(rows), base)
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			for rows.Next() {
+				v, err := p.scan(rows)
+				if err != nil {
+					return err
+				}
+				direct.Set(reflect.Append(direct, v))
+			}
+			return rows.Err()
+		}
+	}
+
 	scannable := isScannable(base)
 
 	if structOnly && scannable {
@@ -296,6 +459,23 @@ func scanAll(rows rowsi, dest any, structOnly bool) error {
 		return err
 	}
 
+	// maps take every column of the row, as MapScan does
+	if base == _rowMapType {
+		for rows.Next() {
+			vp = reflect.New(base)
+			vp.Elem().Set(reflect.MakeMapWithSize(base, len(columns)))
+			if err = MapScan(rows, vp.Elem().Interface().(map[string]any)); err != nil {
+				return err
+			}
+			if isPtr {
+				direct.Set(reflect.Append(direct, vp))
+			} else {
+				direct.Set(reflect.Append(direct, vp.Elem()))
+			}
+		}
+		return rows.Err()
+	}
+
 	// if it's a base type make sure it only has 1 column;  if not return an error
 	if scannable && len(columns) > 1 {
 		return fmt.Errorf("non-struct dest type %s with >1 columns (%d)", base.Kind(), len(columns))
@@ -303,42 +483,53 @@ func scanAll(rows rowsi, dest any, structOnly bool) error {
 
 	if !scannable {
 		var values []any
-		var m *reflectx.Mapper
+		m := rowsMapper(rows)
 
-		switch rows := rows.(type) {
-		case *Rows:
-			m = rows.Mapper
-		default:
-			m = mapper()
+		s, err := newStructScanner(m, base, columns, isNullTolerant(rows))
+		if err != nil {
+			return err
 		}
-
-		fields := m.TraversalsByName(base, columns)
 		// if we are not unsafe and are missing fields, return an error
-		if f, err := missingFields(fields); err != nil && !isUnsafe(rows) {
+		if f, err := missingFields(s.fields); err != nil && !isUnsafe(rows) {
 			return fmt.Errorf("missing destination name %s in %T", columns[f], dest)
 		}
+		if isStrict(rows) {
+			if err := s.checkFilled(dest); err != nil {
+				return err
+			}
+		}
 		values = make([]any, len(columns))
 
 		for rows.Next() {
-			// create a new struct type (which returns PtrTo) and indirect it
-			vp = reflect.New(base)
-			v = reflect.Indirect(vp)
-
-			err = fieldsByTraversal(v, fields, values, true)
-			if err != nil {
-				return err
+			n := direct.Len()
+			if isPtr {
+				// create a new struct type (which returns PtrTo) and indirect it
+				vp = reflect.New(base)
+				v = reflect.Indirect(vp)
+			} else {
+				// scan straight into a new element of the slice, which may
+				// hold a value from before the slice was reset
+				direct.Grow(1)
+				direct.SetLen(n + 1)
+				v = direct.Index(n)
+				v.SetZero()
 			}
 
-			// scan into the struct field pointers and append to our results
-			err = rows.Scan(values...)
+			err = s.prepare(v, values)
+			if err == nil {
+				// scan into the struct field pointers
+				err = rows.Scan(values...)
+			}
+			if err == nil {
+				err = s.finish(v)
+			}
 			if err != nil {
+				direct.SetLen(n)
 				return err
 			}
 
 			if isPtr {
 				direct.Set(reflect.Append(direct, vp))
-			} else {
-				direct.Set(reflect.Append(direct, v))
 			}
 		}
 	} else {
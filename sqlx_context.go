@@ -3,10 +3,8 @@ package sqlx
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"os"
 	"path/filepath"
-	"reflect"
 )
 
 // ConnectContext to a database and verify with a ping.
@@ -46,7 +44,8 @@ type ExtContext interface {
 
 // SelectContext executes a query using the provided Queryer, and StructScans
 // each row into dest, which must be a slice.  If the slice elements are
-// scannable, then the result set must have only one column.  Otherwise,
+// scannable, then the result set must have only one column.  Slices of
+// map[string]any get one map per row, as MapScan fills it.  Otherwise,
 // StructScan is used. The *sql.Rows are closed automatically.
 // Any placeholder parameters are replaced with supplied args.
 func SelectContext(ctx context.Context, q QueryerContext, dest any, query string, args ...any) error {
@@ -68,12 +67,30 @@ func PreparexContext(ctx context.Context, p PreparerContext, query string) (*Stm
 	if err != nil {
 		return nil, err
 	}
-	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p)}, err
+	return &Stmt{Stmt: s, unsafe: isUnsafe(p), strict: isStrict(p), nullZero: isNullTolerant(p), Mapper: mapperFor(p)}, err
+}
+
+// SelectEachContext executes a query using the provided QueryerContext, and
+// scans each row into dest, calling fn after every row.  Unlike Select, dest is
+// a single value which is reused for every row, so that results need not be
+// held in memory.  If dest is scannable, the result set must have only one
+// column.  Otherwise, StructScan is used.  Iteration stops at the first error
+// returned by fn, which is returned.  The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func SelectEachContext(ctx context.Context, q QueryerContext, dest any, fn func() error, query string, args ...any) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanEach(rows, dest, fn)
 }
 
 // GetContext does a QueryRow using the provided Queryer, and scans the
 // resulting row to dest.  If dest is scannable, the result must only have one
-// column. Otherwise, StructScan is used.  Get will return sql.ErrNoRows like
+// column. Otherwise, StructScan is used.  Several destinations can be given
+// with Dests.  Get will return sql.ErrNoRows like
 // row.Scan would. Any placeholder parameters are replaced with supplied args.
 // An error is returned if the result set is empty.
 func GetContext(ctx context.Context, q QueryerContext, dest any, query string, args ...any) error {
@@ -114,6 +131,16 @@ func (db *DB) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt
 	return prepareNamedContext(ctx, db, query)
 }
 
+// PrepareNamedValidatedContext returns an sqlx.NamedStmt once the names of the
+// query are checked against the type of sample as by ValidateNamed.  The
+// sample may also be a reflect.Type.
+func (db *DB) PrepareNamedValidatedContext(ctx context.Context, query string, sample any) (*NamedStmt, error) {
+	if err := validateSample(db, query, sample); err != nil {
+		return nil, err
+	}
+	return prepareNamedContext(ctx, db, query)
+}
+
 // NamedQueryContext using this DB.
 // Any named placeholder parameters are replaced with fields from arg.
 func (db *DB) NamedQueryContext(ctx context.Context, query string, arg any) (*Rows, error) {
@@ -154,14 +181,14 @@ func (db *DB) QueryxContext(ctx context.Context, query string, args ...any) (*Ro
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Rows{Rows: r, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper}, err
 }
 
 // QueryRowxContext queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
 	rows, err := db.DB.QueryContext(ctx, query, args...)
-	return &Row{rows: rows, err: err, unsafe: db.unsafe, Mapper: db.Mapper}
+	return &Row{rows: rows, err: err, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper}
 }
 
 // MustBeginTx starts a transaction, and panics on error.  Returns an *sqlx.Tx instead
@@ -197,7 +224,7 @@ func (db *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper, namedCache: db.namedCache, optionalNamed: db.optionalNamed, namedDefaults: db.namedDefaults}, err
 }
 
 // Connx returns an *sqlx.Conn instead of an *sql.Conn.
@@ -207,34 +234,24 @@ func (db *DB) Connx(ctx context.Context) (*Conn, error) {
 		return nil, err
 	}
 
-	return &Conn{Conn: conn, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, nil
+	return &Conn{Conn: conn, driverName: db.driverName, unsafe: db.unsafe, strict: db.strict, nullZero: db.nullZero, Mapper: db.Mapper, namedCache: db.namedCache, optionalNamed: db.optionalNamed, namedDefaults: db.namedDefaults}, nil
 }
 
 // StmtxContext returns a version of the prepared statement which runs within a
 // transaction. Provided stmt can be either *sql.Stmt or *sqlx.Stmt.
 func (tx *Tx) StmtxContext(ctx context.Context, stmt any) *Stmt {
-	var s *sql.Stmt
-	switch v := stmt.(type) {
-	case Stmt:
-		s = v.Stmt
-	case *Stmt:
-		s = v.Stmt
-	case *sql.Stmt:
-		s = v
-	default:
-		panic(fmt.Sprintf("non-statement type %v passed to Stmtx", reflect.ValueOf(stmt).Type()))
-	}
-	return &Stmt{Stmt: tx.StmtContext(ctx, s), Mapper: tx.Mapper}
+	return tx.stmtx(stmt, func(s *sql.Stmt) *sql.Stmt {
+		return tx.StmtContext(ctx, s)
+	})
 }
 
 // NamedStmtContext returns a version of the prepared statement which runs
 // within a transaction.
 func (tx *Tx) NamedStmtContext(ctx context.Context, stmt *NamedStmt) *NamedStmt {
-	return &NamedStmt{
-		QueryString: stmt.QueryString,
-		Params:      stmt.Params,
-		Stmt:        tx.StmtxContext(ctx, stmt.Stmt),
-	}
+	c := *stmt
+	c.Stmt = tx.StmtxContext(ctx, stmt.Stmt)
+	c.shapes = stmt.shapes.on(tx)
+	return &c
 }
 
 // PreparexContext returns an sqlx.Stmt instead of a sql.Stmt.
@@ -250,6 +267,16 @@ func (tx *Tx) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt
 	return prepareNamedContext(ctx, tx, query)
 }
 
+// PrepareNamedValidatedContext returns an sqlx.NamedStmt once the names of the
+// query are checked against the type of sample as by ValidateNamed.  The
+// sample may also be a reflect.Type.
+func (tx *Tx) PrepareNamedValidatedContext(ctx context.Context, query string, sample any) (*NamedStmt, error) {
+	if err := validateSample(tx, query, sample); err != nil {
+		return nil, err
+	}
+	return prepareNamedContext(ctx, tx, query)
+}
+
 // MustExecContext runs MustExecContext within a transaction.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) MustExecContext(ctx context.Context, query string, args ...any) sql.Result {
@@ -263,7 +290,7 @@ func (tx *Tx) QueryxContext(ctx context.Context, query string, args ...any) (*Ro
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: tx.unsafe, Mapper: tx.Mapper}, err
+	return &Rows{Rows: r, unsafe: tx.unsafe, strict: tx.strict, nullZero: tx.nullZero, Mapper: tx.Mapper}, err
 }
 
 // SelectContext within a transaction and context.
@@ -283,7 +310,7 @@ func (tx *Tx) GetContext(ctx context.Context, dest any, query string, args ...an
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
 	rows, err := tx.Tx.QueryContext(ctx, query, args...)
-	return &Row{rows: rows, err: err, unsafe: tx.unsafe, Mapper: tx.Mapper}
+	return &Row{rows: rows, err: err, unsafe: tx.unsafe, strict: tx.strict, nullZero: tx.nullZero, Mapper: tx.Mapper}
 }
 
 // NamedExecContext using this Tx.
@@ -326,6 +353,13 @@ func (s *Stmt) QueryxContext(ctx context.Context, args ...any) (*Rows, error) {
 	return qs.QueryxContext(ctx, "", args...)
 }
 
+// AsQueryer returns a QueryerContext which runs this prepared statement,
+// ignoring the query argument.  It allows a Stmt to be used with functions
+// like SelectAll and GetOne.
+func (s *Stmt) AsQueryer() QueryerContext {
+	return &qStmt{s}
+}
+
 func (q *qStmt) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	return q.Stmt.QueryContext(ctx, args...)
 }
@@ -335,12 +369,12 @@ func (q *qStmt) QueryxContext(ctx context.Context, query string, args ...any) (*
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: q.Stmt.unsafe, Mapper: q.Stmt.Mapper}, err
+	return &Rows{Rows: r, unsafe: q.Stmt.unsafe, strict: q.Stmt.strict, nullZero: q.Stmt.nullZero, Mapper: q.Stmt.Mapper}, err
 }
 
 func (q *qStmt) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
 	rows, err := q.Stmt.QueryContext(ctx, args...)
-	return &Row{rows: rows, err: err, unsafe: q.Stmt.unsafe, Mapper: q.Stmt.Mapper}
+	return &Row{rows: rows, err: err, unsafe: q.Stmt.unsafe, strict: q.Stmt.strict, nullZero: q.Stmt.nullZero, Mapper: q.Stmt.Mapper}
 }
 
 func (q *qStmt) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
@@ -1366,7 +1366,7 @@ func TestBindMap(t *testing.T) {
 		"last":  "Moiron",
 	}
 
-	bq, args, _ := bindMap(binder.QUESTION, q1, am)
+	bq, args, _ := bindMap(binder.QUESTION, q1, am, mapper(), nil)
 	expect := `INSERT INTO foo (a, b, c, d) VALUES (?, ?, ?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1628,7 +1628,7 @@ func TestBindStruct(t *testing.T) {
 
 	am := tt{"Jason Moiron", 30, "Jason", "Moiron"}
 
-	bq, args, _ := bindStruct(binder.QUESTION, q1, am, mapper())
+	bq, args, _ := bindStruct(binder.QUESTION, q1, am, mapper(), nil)
 	expect := `INSERT INTO foo (a, b, c, d) VALUES (?, ?, ?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1651,7 +1651,7 @@ func TestBindStruct(t *testing.T) {
 	}
 
 	am2 := tt2{"Hello", "World"}
-	bq, args, _ = bindStruct(binder.QUESTION, "INSERT INTO foo (a, b) VALUES (:field_2, :field_1)", am2, mapper())
+	bq, args, _ = bindStruct(binder.QUESTION, "INSERT INTO foo (a, b) VALUES (:field_2, :field_1)", am2, mapper(), nil)
 	expect = `INSERT INTO foo (a, b) VALUES (?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1668,7 +1668,7 @@ func TestBindStruct(t *testing.T) {
 	am3.Field1 = "Hello"
 	am3.Field2 = "World"
 
-	bq, args, err = bindStruct(binder.QUESTION, "INSERT INTO foo (a, b, c) VALUES (:name, :field_1, :field_2)", am3, mapper())
+	bq, args, err = bindStruct(binder.QUESTION, "INSERT INTO foo (a, b, c) VALUES (:name, :field_1, :field_2)", am3, mapper(), nil)
 
 	if err != nil {
 		t.Fatal(err)
@@ -1744,7 +1744,7 @@ func BenchmarkBindStruct(b *testing.B) {
 	am := t{"Jason Moiron", 30, "Jason", "Moiron"}
 	b.StartTimer()
 	for b.Loop() {
-		bindStruct(binder.DOLLAR, q1, am, mapper())
+		bindStruct(binder.DOLLAR, q1, am, mapper(), nil)
 	}
 }
 
@@ -1753,7 +1753,7 @@ func TestBindNamedMapper(t *testing.T) {
 	m := reflectx.NewMapperFunc("db", mpr.Name)
 	query, args, err := bindNamedMapper(binder.DOLLAR, `select :x`, A{
 		"x": "X!",
-	}, m)
+	}, m, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1766,7 +1766,7 @@ func TestBindNamedMapper(t *testing.T) {
 
 	_, _, err = bindNamedMapper(binder.DOLLAR, `select :x`, map[string]string{
 		"x": "X!",
-	}, m)
+	}, m, nil)
 	if err == nil {
 		t.Fatal("err is nil")
 	}
@@ -1786,7 +1786,7 @@ func BenchmarkBindMap(b *testing.B) {
 	}
 	b.StartTimer()
 	for b.Loop() {
-		bindMap(binder.DOLLAR, q1, am)
+		bindMap(binder.DOLLAR, q1, am, mapper(), nil)
 	}
 }
 
@@ -1923,3 +1923,175 @@ func TestSelectReset(t *testing.T) {
 		}
 	})
 }
+
+func TestScanAllReusedSlice(t *testing.T) {
+	// elements are scanned in place, so values left in the backing array
+	// from before the reset must not leak into the new rows
+	dest := []benchPerson{{ID: 9, Email: "old@example.com"}, {ID: 8}}
+	rows := &benchRows{columns: []string{"id", "first_name"}, row: []any{1, "Jason"}, n: 1}
+	if err := scanAll(rows, &dest, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(dest) != 1 || dest[0].ID != 1 || dest[0].First != "Jason" || dest[0].Email != "" {
+		t.Errorf("unexpected rows: %#v", dest)
+	}
+
+	// a row failing to scan is not left in the slice
+	type ratio struct {
+		F float64 `db:"f"`
+	}
+	ratios := []ratio{{F: 1}}
+	rows = &benchRows{columns: []string{"f"}, row: []any{0.5}, n: 1}
+	if err := scanAll(rows, &ratios, false); err == nil || len(ratios) != 0 {
+		t.Errorf("expected an error and no rows, got %v %#v", err, ratios)
+	}
+}
+
+// benchRows is an in-memory result set returning the same row n times, used to
+// measure scanning without a database.
+type benchRows struct {
+	columns []string
+	row     []any
+	n       int
+}
+
+func (r *benchRows) Close() error               { return nil }
+func (r *benchRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *benchRows) Err() error                 { return nil }
+
+func (r *benchRows) Next() bool {
+	r.n--
+	return r.n >= 0
+}
+
+func (r *benchRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *string:
+			*d = r.row[i].(string)
+		case *int:
+			*d = r.row[i].(int)
+		case *any:
+			*d = r.row[i]
+		default:
+			return fmt.Errorf("unsupported destination %T", d)
+		}
+	}
+	return nil
+}
+
+type benchPerson struct {
+	ID      int    `db:"id"`
+	First   string `db:"first_name"`
+	Last    string `db:"last_name"`
+	Email   string `db:"email"`
+	Address struct {
+		City    string `db:"city"`
+		Country string `db:"country"`
+	} `db:"address"`
+}
+
+var benchColumns = []string{"id", "first_name", "last_name", "email", "address.city", "address.country"}
+var benchRow = []any{1, "Jason", "Moiron", "jmoiron@jmoiron.net", "New York", "United States"}
+
+// BenchmarkScanAll scans through the cached scan plans.
+func BenchmarkScanAll(b *testing.B) {
+	for b.Loop() {
+		var dest []benchPerson
+		rows := &benchRows{columns: benchColumns, row: benchRow, n: 100}
+		if err := scanAll(rows, &dest, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkScanAllTraversals scans as scanAll did before scan plans, resolving
+// the columns for every query and walking the traversals for every row.
+func BenchmarkScanAllTraversals(b *testing.B) {
+	m := mapper()
+	base := reflect.TypeFor[benchPerson]()
+	for b.Loop() {
+		var dest []benchPerson
+		rows := &benchRows{columns: benchColumns, row: benchRow, n: 100}
+		direct := reflect.ValueOf(&dest).Elem()
+		fields := m.TraversalsByName(base, benchColumns)
+		values := make([]any, len(benchColumns))
+		for rows.Next() {
+			v := reflect.New(base).Elem()
+			if err := fieldsByTraversal(v, fields, values, true); err != nil {
+				b.Fatal(err)
+			}
+			if err := rows.Scan(values...); err != nil {
+				b.Fatal(err)
+			}
+			direct.Set(reflect.Append(direct, v))
+		}
+	}
+}
+
+// BenchmarkScanRow prepares and scans a single row through a structScanner,
+// leaving out the allocation and appending of the destination done by scanAll.
+func BenchmarkScanRow(b *testing.B) {
+	base := reflect.TypeFor[benchPerson]()
+	s, err := newStructScanner(mapper(), base, benchColumns, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rows := &benchRows{columns: benchColumns, row: benchRow}
+	v := reflect.New(base).Elem()
+	values := make([]any, len(benchColumns))
+	for b.Loop() {
+		if err := s.prepare(v, values); err != nil {
+			b.Fatal(err)
+		}
+		if err := rows.Scan(values...); err != nil {
+			b.Fatal(err)
+		}
+		if err := s.finish(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkScanRowTraversals prepares and scans a single row by walking the
+// traversals, as scanAll did before scan plans.
+func BenchmarkScanRowTraversals(b *testing.B) {
+	base := reflect.TypeFor[benchPerson]()
+	fields := mapper().TraversalsByName(base, benchColumns)
+	rows := &benchRows{columns: benchColumns, row: benchRow}
+	v := reflect.New(base).Elem()
+	values := make([]any, len(benchColumns))
+	for b.Loop() {
+		if err := fieldsByTraversal(v, fields, values, true); err != nil {
+			b.Fatal(err)
+		}
+		if err := rows.Scan(values...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkScanSetup builds the structScanner of a query, which scan plans
+// share between queries returning the same columns.
+func BenchmarkScanSetup(b *testing.B) {
+	m := mapper()
+	base := reflect.TypeFor[benchPerson]()
+	for b.Loop() {
+		if _, err := newStructScanner(m, base, benchColumns, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkScanSetupTraversals resolves the columns of a query as scanAll did
+// before scan plans.
+func BenchmarkScanSetupTraversals(b *testing.B) {
+	m := mapper()
+	base := reflect.TypeFor[benchPerson]()
+	for b.Loop() {
+		fields := m.TraversalsByName(base, benchColumns)
+		if _, err := missingFields(fields); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -9,14 +9,34 @@ import (
 // Stmt is an sqlx wrapper around sql.Stmt with extra functionality
 type Stmt struct {
 	*sql.Stmt
-	unsafe bool
-	Mapper *reflectx.Mapper
+	unsafe   bool
+	strict   bool
+	nullZero bool
+	Mapper   *reflectx.Mapper
 }
 
 // Unsafe returns a version of Stmt which will silently succeed to scan when
 // columns in the SQL result have no fields in the destination struct.
 func (s *Stmt) Unsafe() *Stmt {
-	return &Stmt{Stmt: s.Stmt, unsafe: true, Mapper: s.Mapper}
+	c := *s
+	c.unsafe = true
+	return &c
+}
+
+// Strict returns a version of Stmt which will fail to scan when fields of the
+// destination struct are not filled by any column in the SQL result.
+func (s *Stmt) Strict() *Stmt {
+	c := *s
+	c.strict = true
+	return &c
+}
+
+// NullTolerant returns a version of Stmt which scans NULL into the zero value
+// of struct fields which cannot hold NULL instead of failing.
+func (s *Stmt) NullTolerant() *Stmt {
+	c := *s
+	c.nullZero = true
+	return &c
 }
 
 // Select using the prepared statement.
@@ -66,12 +86,12 @@ func (q *qStmt) Queryx(query string, args ...any) (*Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: q.Stmt.unsafe, Mapper: q.Stmt.Mapper}, err
+	return &Rows{Rows: r, unsafe: q.Stmt.unsafe, strict: q.Stmt.strict, nullZero: q.Stmt.nullZero, Mapper: q.Stmt.Mapper}, err
 }
 
 func (q *qStmt) QueryRowx(query string, args ...any) *Row {
 	rows, err := q.Stmt.Query(args...)
-	return &Row{rows: rows, err: err, unsafe: q.Stmt.unsafe, Mapper: q.Stmt.Mapper}
+	return &Row{rows: rows, err: err, unsafe: q.Stmt.unsafe, strict: q.Stmt.strict, nullZero: q.Stmt.nullZero, Mapper: q.Stmt.Mapper}
 }
 
 func (q *qStmt) Exec(query string, args ...any) (sql.Result, error) {
@@ -14,7 +14,15 @@ type Tx struct {
 	*sql.Tx
 	driverName string
 	unsafe     bool
+	strict     bool
+	nullZero   bool
 	Mapper     *reflectx.Mapper
+	namedCache *NamedCache
+	// optionalNamed makes all named parameters optional, see OptionalNamed.
+	optionalNamed bool
+	// namedDefaults are bound to missing optional parameters, see
+	// NamedDefaults.
+	namedDefaults map[string]any
 }
 
 // DriverName returns the driverName used by the DB which began this transaction.
@@ -30,12 +38,47 @@ func (tx *Tx) Rebind(query string) string {
 // Unsafe returns a version of Tx which will silently succeed to scan when
 // columns in the SQL result have no fields in the destination struct.
 func (tx *Tx) Unsafe() *Tx {
-	return &Tx{Tx: tx.Tx, driverName: tx.driverName, unsafe: true, Mapper: tx.Mapper}
+	c := *tx
+	c.unsafe = true
+	return &c
+}
+
+// Strict returns a version of Tx which will fail to scan when fields of the
+// destination struct are not filled by any column in the SQL result.
+func (tx *Tx) Strict() *Tx {
+	c := *tx
+	c.strict = true
+	return &c
+}
+
+// NullTolerant returns a version of Tx which scans NULL into the zero value of
+// struct fields which cannot hold NULL instead of failing.
+func (tx *Tx) NullTolerant() *Tx {
+	c := *tx
+	c.nullZero = true
+	return &c
+}
+
+// OptionalNamed returns a version of Tx on which every parameter of named
+// queries is optional, binding its default or NULL when missing.
+func (tx *Tx) OptionalNamed() *Tx {
+	c := *tx
+	c.optionalNamed = true
+	return &c
+}
+
+// NamedDefaults returns a version of Tx which binds optional named parameters
+// missing from the arg to their value in defaults rather than NULL, as
+// DB.NamedDefaults does.
+func (tx *Tx) NamedDefaults(defaults map[string]any) *Tx {
+	c := *tx
+	c.namedDefaults = mergeDefaults(tx.namedDefaults, defaults)
+	return &c
 }
 
 // BindNamed binds a query within a transaction's bindvar type.
 func (tx *Tx) BindNamed(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(binder.Default.Type(tx.driverName), query, arg, tx.Mapper)
+	return bindNamedMapper(binder.Default.Type(tx.driverName), query, optionalFor(tx, arg), tx.Mapper, namedCacheFor(tx))
 }
 
 // NamedQuery within a transaction.
@@ -63,14 +106,14 @@ func (tx *Tx) Queryx(query string, args ...any) (*Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Rows{Rows: r, unsafe: tx.unsafe, Mapper: tx.Mapper}, err
+	return &Rows{Rows: r, unsafe: tx.unsafe, strict: tx.strict, nullZero: tx.nullZero, Mapper: tx.Mapper}, err
 }
 
 // QueryRowx within a transaction.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) QueryRowx(query string, args ...any) *Row {
 	rows, err := tx.Tx.Query(query, args...)
-	return &Row{rows: rows, err: err, unsafe: tx.unsafe, Mapper: tx.Mapper}
+	return &Row{rows: rows, err: err, unsafe: tx.unsafe, strict: tx.strict, nullZero: tx.nullZero, Mapper: tx.Mapper}
 }
 
 // Get within a transaction.
@@ -94,30 +137,49 @@ func (tx *Tx) Preparex(query string) (*Stmt, error) {
 // Stmtx returns a version of the prepared statement which runs within a transaction.  Provided
 // stmt can be either *sql.Stmt or *sqlx.Stmt.
 func (tx *Tx) Stmtx(stmt any) *Stmt {
-	var s *sql.Stmt
+	return tx.stmtx(stmt, tx.Stmt)
+}
+
+// stmtx returns stmt run within tx by txStmt.  The scanning modes of an
+// sqlx.Stmt are kept, and those set on tx are added to them.
+func (tx *Tx) stmtx(stmt any, txStmt func(*sql.Stmt) *sql.Stmt) *Stmt {
+	var c Stmt
 	switch v := stmt.(type) {
 	case Stmt:
-		s = v.Stmt
+		c = v
 	case *Stmt:
-		s = v.Stmt
+		c = *v
 	case *sql.Stmt:
-		s = v
+		c.Stmt = v
 	default:
 		panic(fmt.Sprintf("non-statement type %v passed to Stmtx", reflect.ValueOf(stmt).Type()))
 	}
-	return &Stmt{Stmt: tx.Stmt(s), Mapper: tx.Mapper}
+	c.unsafe = c.unsafe || tx.unsafe
+	c.strict = c.strict || tx.strict
+	c.nullZero = c.nullZero || tx.nullZero
+	c.Stmt, c.Mapper = txStmt(c.Stmt), tx.Mapper
+	return &c
 }
 
 // NamedStmt returns a version of the prepared statement which runs within a transaction.
 func (tx *Tx) NamedStmt(stmt *NamedStmt) *NamedStmt {
-	return &NamedStmt{
-		QueryString: stmt.QueryString,
-		Params:      stmt.Params,
-		Stmt:        tx.Stmtx(stmt.Stmt),
-	}
+	c := *stmt
+	c.Stmt = tx.Stmtx(stmt.Stmt)
+	c.shapes = stmt.shapes.on(tx)
+	return &c
 }
 
 // PrepareNamed returns an sqlx.NamedStmt
 func (tx *Tx) PrepareNamed(query string) (*NamedStmt, error) {
 	return prepareNamed(tx, query)
 }
+
+// PrepareNamedValidated returns an sqlx.NamedStmt once the names of the query
+// are checked against the type of sample as by ValidateNamed.  The sample may
+// also be a reflect.Type.
+func (tx *Tx) PrepareNamedValidated(query string, sample any) (*NamedStmt, error) {
+	if err := validateSample(tx, query, sample); err != nil {
+		return nil, err
+	}
+	return prepareNamed(tx, query)
+}